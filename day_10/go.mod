@@ -0,0 +1,3 @@
+module day_10
+
+go 1.22.1
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// the height of a cell no trail can go through
+const Impassable = ^uint(0)
+
+// which steps a trail can take, and where trails start and end
+type TrailRules struct {
+	minRise, maxRise int    // a step changes the height by minRise to maxRise, a negative rise is a descent
+	diagonal         bool   // steps can also be diagonal
+	impassable       string // the characters of the map which are impassable cells
+	startHeight      uint
+	endHeight        uint
+}
+
+// the rules of the puzzle: orthogonal steps exactly one higher, from height 0 to height 9
+var DefaultTrailRules = TrailRules{minRise: 1, maxRise: 1, impassable: ".", startHeight: 0, endHeight: 9}
+
+// up, down, left, right, then the diagonals
+var stepOffsets = []Location{{-1, 0}, {1, 0}, {0, -1}, {0, 1}, {-1, -1}, {-1, 1}, {1, -1}, {1, 1}}
+
+func (r TrailRules) canStep(from, to uint) bool {
+	if from == Impassable || to == Impassable {
+		return false
+	}
+	rise := int(to) - int(from)
+	return rise >= r.minRise && rise <= r.maxRise
+}
+
+// trails can only go up, or only go down, so they can't come back to a cell
+func (r TrailRules) acyclic() bool {
+	return r.minRise > 0 || r.maxRise < 0
+}
+
+// parse the rows of the map, a digit is the height of a cell, an impassable character an impassable cell
+func ParseTopoMap(lines []string, rules TrailRules) ([][]uint, error) {
+	topomap := make([][]uint, 0, len(lines))
+	for i, line := range lines {
+		row := make([]uint, 0, len(line))
+		for j, c := range line {
+			switch {
+			case c >= '0' && c <= '9':
+				row = append(row, uint(c-'0'))
+			case strings.ContainsRune(rules.impassable, c):
+				row = append(row, Impassable)
+			default:
+				return nil, fmt.Errorf("invalid character %q at %d,%d", c, i, j)
+			}
+		}
+		topomap = append(topomap, row)
+	}
+	return topomap, nil
+}
+
+// convert the topomap into a graph, with an edge for every step the rules allow
+func MapToGraphWithRules(topomap [][]uint, rules TrailRules) Graph {
+	offsets := stepOffsets[:4]
+	if rules.diagonal {
+		offsets = stepOffsets
+	}
+
+	graph := Graph{make(map[Node][]Node), rules}
+	for i, row := range topomap {
+		for j, height := range row {
+			if height == Impassable {
+				continue
+			}
+			node := Node{Location{i, j}, height}
+			graph.adj[node] = make([]Node, 0)
+
+			for _, offset := range offsets {
+				x, y := i+offset.x, j+offset.y
+				if x < 0 || x >= len(topomap) || y < 0 || y >= len(topomap[x]) {
+					continue
+				}
+				if rules.canStep(height, topomap[x][y]) {
+					graph.adj[node] = append(graph.adj[node], Node{Location{x, y}, topomap[x][y]})
+				}
+			}
+		}
+	}
+	return graph
+}
+
+// the nodes at the start height, ordered by location
+func (g Graph) Trailheads() []Node {
+	trailheads := make([]Node, 0)
+	for node := range g.adj {
+		if node.height == g.rules.startHeight {
+			trailheads = append(trailheads, node)
+		}
+	}
+	sortNodes(trailheads)
+	return trailheads
+}
+
+// the cells of a trail, from the trailhead to the end
+type Trail []Location
+
+func (t Trail) String() string {
+	steps := make([]string, len(t))
+	for i, loc := range t {
+		steps[i] = fmt.Sprintf("(%d,%d)", loc.x, loc.y)
+	}
+	return strings.Join(steps, " -> ")
+}
+
+// enumerate the trails from the trailhead, depth first, stopping after limit trails when limit > 0.
+// A trail never visits a cell twice, and ends the first time it reaches the end height
+func (g Graph) TrailsFrom(trailhead Node, limit int) []Trail {
+	trails := make([]Trail, 0)
+	onTrail := make(map[Node]bool)
+	trail := make(Trail, 0)
+
+	var walk func(node Node) bool
+	walk = func(node Node) bool {
+		trail = append(trail, node.loc)
+		defer func() { trail = trail[:len(trail)-1] }()
+
+		if node.height == g.rules.endHeight {
+			trails = append(trails, append(Trail(nil), trail...))
+			return limit > 0 && len(trails) >= limit
+		}
+
+		onTrail[node] = true
+		defer delete(onTrail, node)
+		for _, neighbor := range g.adj[node] {
+			if !onTrail[neighbor] && walk(neighbor) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if _, ok := g.adj[trailhead]; ok && trailhead.height == g.rules.startHeight {
+		walk(trailhead)
+	}
+	return trails
+}
+
+// how many random walks to try per trail asked for before giving up
+const maxSampleAttempts = 100
+
+// sample up to n trails from the trailhead with random walks: each step goes to a random neighbor not already on the
+// trail, and walks which get stuck before the end height are dropped. Trails with fewer choices along the way are
+// more likely, and the same trail can be sampled more than once
+func (g Graph) SampleTrails(trailhead Node, n int, rng *rand.Rand) []Trail {
+	trails := make([]Trail, 0, n)
+	if _, ok := g.adj[trailhead]; !ok || trailhead.height != g.rules.startHeight {
+		return trails
+	}
+
+	for attempt := 0; attempt < n*maxSampleAttempts && len(trails) < n; attempt++ {
+		onTrail := map[Node]bool{trailhead: true}
+		trail := Trail{trailhead.loc}
+		node := trailhead
+		for node.height != g.rules.endHeight {
+			choices := make([]Node, 0, len(g.adj[node]))
+			for _, neighbor := range g.adj[node] {
+				if !onTrail[neighbor] {
+					choices = append(choices, neighbor)
+				}
+			}
+			if len(choices) == 0 {
+				trail = nil
+				break
+			}
+			node = choices[rng.Intn(len(choices))]
+			onTrail[node] = true
+			trail = append(trail, node.loc)
+		}
+		if trail != nil {
+			trails = append(trails, trail)
+		}
+	}
+	return trails
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+const threeTrails = `.....0.
+..4321.
+..5..2.
+..6543.
+..7..4.
+..8765.
+..9....`
+
+func TestParseTopoMap(t *testing.T) {
+	topomap, err := ParseTopoMap([]string{"0.", "19"}, DefaultTrailRules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][]uint{{0, Impassable}, {1, 9}}
+	if !slices.EqualFunc(topomap, want, slices.Equal) {
+		t.Errorf("want %v, got %v", want, topomap)
+	}
+
+	if _, err := ParseTopoMap([]string{"0x"}, DefaultTrailRules); err == nil {
+		t.Errorf("want an error for an invalid character")
+	}
+}
+
+func TestCustomTrailRules(t *testing.T) {
+	testCases := []struct {
+		name          string
+		topomap       string
+		rules         TrailRules
+		score, rating int
+	}{
+		{"downhill from the summits", example,
+			TrailRules{minRise: -1, maxRise: -1, impassable: ".", startHeight: 9, endHeight: 0}, 36, 81},
+		{"diagonal", "0.\n.1",
+			TrailRules{minRise: 1, maxRise: 1, diagonal: true, impassable: ".", startHeight: 0, endHeight: 1}, 1, 1},
+		{"no diagonal", "0.\n.1",
+			TrailRules{minRise: 1, maxRise: 1, impassable: ".", startHeight: 0, endHeight: 1}, 0, 0},
+		{"steeper steps", "0.\n2.\n4#",
+			TrailRules{minRise: 1, maxRise: 2, impassable: ".#", startHeight: 0, endHeight: 4}, 1, 1},
+		// trails can go back down, so the graph has cycles and the trails are enumerated
+		{"up and down", "01\n12",
+			TrailRules{minRise: -1, maxRise: 1, impassable: ".", startHeight: 0, endHeight: 2}, 1, 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			score, rating := 0, 0
+			for _, trailheadScore := range parseGraph(t, tc.topomap, tc.rules).ScoreTrailheads() {
+				score += trailheadScore.score
+				rating += trailheadScore.rating
+			}
+			if score != tc.score || rating != tc.rating {
+				t.Errorf("want score %d and rating %d, got %d and %d", tc.score, tc.rating, score, rating)
+			}
+		})
+	}
+}
+
+// check the trail goes from the trailhead to a summit one step up at a time
+func checkTrail(t *testing.T, graph Graph, trailhead Node, trail Trail) {
+	t.Helper()
+	if trail[0] != trailhead.loc {
+		t.Errorf("%v: want a trail from %v", trail, trailhead.loc)
+	}
+	for i := 1; i < len(trail); i++ {
+		from, to := trail[i-1], trail[i]
+		if !slices.ContainsFunc(graph.adj[Node{from, uint(i - 1)}], func(n Node) bool { return n.loc == to }) {
+			t.Errorf("%v: no step from %v to %v", trail, from, to)
+		}
+	}
+	if len(trail) != 10 {
+		t.Errorf("%v: want a trail up to height 9", trail)
+	}
+}
+
+func TestTrailsFrom(t *testing.T) {
+	graph := parseGraph(t, threeTrails, DefaultTrailRules)
+	trailhead := graph.Trailheads()[0]
+
+	trails := graph.TrailsFrom(trailhead, 0)
+	if len(trails) != 3 {
+		t.Fatalf("want 3 trails, got %v", trails)
+	}
+	for i, trail := range trails {
+		checkTrail(t, graph, trailhead, trail)
+		if slices.ContainsFunc(trails[:i], func(other Trail) bool { return slices.Equal(other, trail) }) {
+			t.Errorf("%v: found twice", trail)
+		}
+	}
+
+	if limited := graph.TrailsFrom(trailhead, 1); len(limited) != 1 || !slices.Equal(limited[0], trails[0]) {
+		t.Errorf("want only %v, got %v", trails[0], limited)
+	}
+	if none := graph.TrailsFrom(Node{Location{1, 5}, 1}, 0); len(none) != 0 {
+		t.Errorf("want no trails from a node which is not a trailhead, got %v", none)
+	}
+
+	if got, want := (Trail{{0, 5}, {1, 5}}).String(), "(0,5) -> (1,5)"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestSampleTrails(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+
+	t.Run("trails of the map", func(t *testing.T) {
+		graph := parseGraph(t, threeTrails, DefaultTrailRules)
+		trailhead := graph.Trailheads()[0]
+		all := graph.TrailsFrom(trailhead, 0)
+
+		samples := graph.SampleTrails(trailhead, 20, rng)
+		if len(samples) != 20 {
+			t.Fatalf("want 20 trails, got %d", len(samples))
+		}
+		for _, sample := range samples {
+			if !slices.ContainsFunc(all, func(trail Trail) bool { return slices.Equal(trail, sample) }) {
+				t.Errorf("%v: not a trail of the map", sample)
+			}
+		}
+	})
+
+	t.Run("dead ends are dropped", func(t *testing.T) {
+		graph := parseGraph(t, "0123456789\n1.........", DefaultTrailRules)
+		trailhead := graph.Trailheads()[0]
+		samples := graph.SampleTrails(trailhead, 5, rng)
+		if len(samples) != 5 {
+			t.Fatalf("want 5 trails, got %d", len(samples))
+		}
+		for _, sample := range samples {
+			checkTrail(t, graph, trailhead, sample)
+		}
+	})
+
+	t.Run("no trail", func(t *testing.T) {
+		graph := parseGraph(t, "0123", DefaultTrailRules)
+		if samples := graph.SampleTrails(graph.Trailheads()[0], 5, rng); len(samples) != 0 {
+			t.Errorf("want no trails, got %v", samples)
+		}
+	})
+}
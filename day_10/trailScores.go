@@ -0,0 +1,119 @@
+package main
+
+import (
+	"cmp"
+	"math/bits"
+	"slices"
+)
+
+// a set of summits, one bit per summit index
+type Bitset []uint64
+
+func NewBitset(size int) Bitset {
+	return make(Bitset, (size+63)/64)
+}
+
+func (b Bitset) Set(i int) {
+	b[i/64] |= 1 << (i % 64)
+}
+
+// add all the members of other to b
+func (b Bitset) Union(other Bitset) {
+	for i := range b {
+		b[i] |= other[i]
+	}
+}
+
+func (b Bitset) Count() int {
+	count := 0
+	for _, word := range b {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// what can be reached from a node: the set of summits, and the number of distinct trails to any summit
+type reachability struct {
+	summits Bitset
+	trails  int
+}
+
+type TrailheadScore struct {
+	trailhead Location
+	score     int // number of summits reachable from the trailhead
+	rating    int // number of distinct trails from the trailhead to a summit
+}
+
+func compareLocations(a, b Location) int {
+	return cmp.Or(cmp.Compare(a.x, b.x), cmp.Compare(a.y, b.y))
+}
+
+func sortNodes(nodes []Node) {
+	slices.SortFunc(nodes, func(a, b Node) int { return compareLocations(a.loc, b.loc) })
+}
+
+// score and rate all the trailheads (the start height) at once. When trails only go up, or only down, the graph is a
+// DAG and the summits and trails reachable from each node are computed once, from those of its neighbors, then shared
+// by all the trailheads. Otherwise the trails of every trailhead are enumerated
+func (g Graph) ScoreTrailheads() []TrailheadScore {
+	if !g.rules.acyclic() {
+		return g.scoreTrailheadsByEnumeration()
+	}
+
+	// number the summits
+	summits := make([]Node, 0)
+	for node := range g.adj {
+		if node.height == g.rules.endHeight {
+			summits = append(summits, node)
+		}
+	}
+	sortNodes(summits)
+	summitIndex := make(map[Node]int, len(summits))
+	for i, summit := range summits {
+		summitIndex[summit] = i
+	}
+
+	memo := make(map[Node]reachability, len(g.adj))
+	var reach func(node Node) reachability
+	reach = func(node Node) reachability {
+		if r, ok := memo[node]; ok {
+			return r
+		}
+
+		r := reachability{NewBitset(len(summits)), 0}
+		if i, ok := summitIndex[node]; ok {
+			r.summits.Set(i)
+			r.trails = 1
+		} else {
+			for _, neighbor := range g.adj[node] {
+				neighborReach := reach(neighbor)
+				r.summits.Union(neighborReach.summits)
+				r.trails += neighborReach.trails
+			}
+		}
+
+		memo[node] = r
+		return r
+	}
+
+	scores := make([]TrailheadScore, 0)
+	for _, trailhead := range g.Trailheads() {
+		r := reach(trailhead)
+		scores = append(scores, TrailheadScore{trailhead.loc, r.summits.Count(), r.trails})
+	}
+	return scores
+}
+
+// score and rate the trailheads from all their trails, for rules which allow a trail to come back to a cell
+func (g Graph) scoreTrailheadsByEnumeration() []TrailheadScore {
+	scores := make([]TrailheadScore, 0)
+	for _, trailhead := range g.Trailheads() {
+		trails := g.TrailsFrom(trailhead, 0)
+		summits := make(map[Location]bool)
+		for _, trail := range trails {
+			summits[trail[len(trail)-1]] = true
+		}
+		scores = append(scores, TrailheadScore{trailhead.loc, len(summits), len(trails)})
+	}
+	return scores
+}
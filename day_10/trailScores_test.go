@@ -0,0 +1,75 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const example = `89010123
+78121874
+87430965
+96549874
+45678903
+32019012
+01329801
+10456732`
+
+func parseGraph(t *testing.T, topomap string, rules TrailRules) Graph {
+	t.Helper()
+	parsed, err := ParseTopoMap(strings.Split(topomap, "\n"), rules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return MapToGraphWithRules(parsed, rules)
+}
+
+func TestScoreTrailheads(t *testing.T) {
+	t.Run("example", func(t *testing.T) {
+		scores := parseGraph(t, example, DefaultTrailRules).ScoreTrailheads()
+
+		wantScores := []int{5, 6, 5, 3, 1, 3, 5, 3, 5}
+		wantRatings := []int{20, 24, 10, 4, 1, 4, 5, 8, 5}
+		gotScores, gotRatings := make([]int, 0), make([]int, 0)
+		totalScore, totalRating := 0, 0
+		for _, score := range scores {
+			gotScores = append(gotScores, score.score)
+			gotRatings = append(gotRatings, score.rating)
+			totalScore += score.score
+			totalRating += score.rating
+		}
+
+		if !slices.Equal(gotScores, wantScores) {
+			t.Errorf("want scores %v, got %v", wantScores, gotScores)
+		}
+		if !slices.Equal(gotRatings, wantRatings) {
+			t.Errorf("want ratings %v, got %v", wantRatings, gotRatings)
+		}
+		if totalScore != 36 || totalRating != 81 {
+			t.Errorf("want 36 and 81, got %d and %d", totalScore, totalRating)
+		}
+	})
+
+	testCases := []struct {
+		name    string
+		topomap string
+		score   int
+		rating  int
+	}{
+		{"one summit", "0123\n1234\n8765\n9876", 1, 16},
+		{"impassable cells", "..90..9\n...1.98\n...2..7\n6543456\n765.987\n876....\n987....", 4, 13},
+		{"three trails", ".....0.\n..4321.\n..5..2.\n..6543.\n..7..4.\n..8765.\n..9....", 1, 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scores := parseGraph(t, tc.topomap, DefaultTrailRules).ScoreTrailheads()
+			if len(scores) != 1 {
+				t.Fatalf("want 1 trailhead, got %v", scores)
+			}
+			if scores[0].score != tc.score || scores[0].rating != tc.rating {
+				t.Errorf("want score %d and rating %d, got %d and %d", tc.score, tc.rating, scores[0].score, scores[0].rating)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+)
+
+// stone numbers and their count, the counts don't overflow however many blinks
+type StoneCounts map[uint64]*big.Int
+
+func SliceToStoneCounts(stones []uint64) StoneCounts {
+	result := make(StoneCounts)
+	for _, stone := range stones {
+		if _, ok := result[stone]; !ok {
+			result[stone] = new(big.Int)
+		}
+		result[stone].Add(result[stone], big.NewInt(1))
+	}
+	return result
+}
+
+func (s StoneCounts) Total() *big.Int {
+	total := new(big.Int)
+	for _, count := range s {
+		total.Add(total, count)
+	}
+	return total
+}
+
+// what happened during one blink
+type BlinkStats struct {
+	blink        int
+	distinct     int      // distinct stone numbers after the blink
+	total        *big.Int // stones after the blink
+	largest      uint64   // the largest stone number after the blink
+	cacheHits    int      // stone numbers whose mutation was already in the cache
+	cacheLookups int
+	err          error // why the blink failed, the stats of a failed blink are the last ones of the stream
+}
+
+func (s BlinkStats) CacheHitRate() float64 {
+	if s.cacheLookups == 0 {
+		return 0
+	}
+	return float64(s.cacheHits) / float64(s.cacheLookups)
+}
+
+// mutate the stones of the map like MutateStoneMap, and report the blink
+func (s *Simulator) mutateStoneCounts(stones StoneCounts) (StoneCounts, BlinkStats, error) {
+	result := make(StoneCounts)
+	stats := BlinkStats{cacheLookups: len(stones)}
+	for stone, count := range stones {
+		newStones, hit, err := s.cache.Mutate(stone, s.rules.Apply)
+		if err != nil {
+			return nil, BlinkStats{}, err
+		}
+		if hit {
+			stats.cacheHits++
+		}
+
+		for _, newStone := range newStones {
+			if _, ok := result[newStone]; !ok {
+				result[newStone] = new(big.Int)
+			}
+			result[newStone].Add(result[newStone], count)
+		}
+	}
+
+	stats.distinct = len(result)
+	stats.total = result.Total()
+	for stone := range result {
+		stats.largest = max(stats.largest, stone)
+	}
+	return result, stats, nil
+}
+
+// blink numberOfBlinks times with the puzzle's rules and the shared cache of the puzzle's simulator
+func StreamBlinks(done <-chan struct{}, stones StoneCounts, numberOfBlinks int) <-chan BlinkStats {
+	return puzzleSimulator.StreamBlinks(done, stones, numberOfBlinks)
+}
+
+// StreamBlinks with the rules, and a cache of their own
+func (rs RuleSet) StreamBlinks(done <-chan struct{}, stones StoneCounts, numberOfBlinks int) <-chan BlinkStats {
+	return NewSimulator(rs, 0).StreamBlinks(done, stones, numberOfBlinks)
+}
+
+// blink numberOfBlinks times, the statistics of each blink are sent on the channel as soon as the blink is done.
+// The channel is closed after the last blink, after a blink which fails, or as soon as done is closed: close done when
+// giving up on the stream before its end, so the blinking stops. A nil done never stops it
+func (s *Simulator) StreamBlinks(done <-chan struct{}, stones StoneCounts, numberOfBlinks int) <-chan BlinkStats {
+	statsStream := make(chan BlinkStats)
+	go func() {
+		defer close(statsStream)
+		for i := 1; i <= numberOfBlinks; i++ {
+			var stats BlinkStats
+			var err error
+			if stones, stats, err = s.mutateStoneCounts(stones); err != nil {
+				stats.err = err
+			}
+			stats.blink = i
+			select {
+			case statsStream <- stats:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return statsStream
+}
+
+// write the statistics as CSV, with a header line, as they arrive. Return the statistics of the last blink, or of the
+// last blink before the one which failed with its error, the zero BlinkStats when no blink ran. On a write error the rest of the stream is not read, close its
+// done channel to stop the blinking
+func WriteBlinkStatsCSV(w io.Writer, statsStream <-chan BlinkStats) (last BlinkStats, err error) {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"blink", "distinct", "total", "largest", "cache_hits", "cache_lookups", "cache_hit_rate"})
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		return last, err
+	}
+	for stats := range statsStream {
+		if stats.err != nil {
+			return last, fmt.Errorf("blink %d: %w", stats.blink, stats.err)
+		}
+		last = stats
+		writer.Write([]string{
+			strconv.Itoa(stats.blink),
+			strconv.Itoa(stats.distinct),
+			stats.total.String(),
+			strconv.FormatUint(stats.largest, 10),
+			strconv.Itoa(stats.cacheHits),
+			strconv.Itoa(stats.cacheLookups),
+			fmt.Sprintf("%.4f", stats.CacheHitRate()),
+		})
+		writer.Flush()
+		if err = writer.Error(); err != nil {
+			return last, err
+		}
+	}
+	return last, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestStreamBlinks(t *testing.T) {
+	stones := []uint64{125, 17}
+
+	t.Run("counts", func(t *testing.T) {
+		var last BlinkStats
+		for stats := range StreamBlinks(nil, SliceToStoneCounts(stones), 25) {
+			last = stats
+		}
+		if last.blink != 25 || last.total.Cmp(big.NewInt(55312)) != 0 {
+			t.Fatalf("expected 55312 stones after 25 blinks, got %v after %d", last.total, last.blink)
+		}
+	})
+
+	t.Run("beyond uint64", func(t *testing.T) {
+		var last BlinkStats
+		for stats := range StreamBlinks(nil, SliceToStoneCounts(stones), 200) {
+			last = stats
+		}
+		if last.total.IsUint64() {
+			t.Fatalf("expected more stones than a uint64 holds, got %v", last.total)
+		}
+	})
+
+	t.Run("statistics", func(t *testing.T) {
+		// 125 17 -> 253000 1 7 -> 253 0 2024 14168 -> 512072 1 20 24 28676032
+		want := []BlinkStats{
+			{1, 3, big.NewInt(3), 253000, 0, 2, nil},
+			{2, 4, big.NewInt(4), 14168, 0, 3, nil},
+			{3, 5, big.NewInt(5), 28676032, 0, 4, nil},
+		}
+		i := 0
+		for got := range PuzzleRules.StreamBlinks(nil, SliceToStoneCounts(stones), len(want)) {
+			w := want[i]
+			if got.blink != w.blink || got.distinct != w.distinct || got.total.Cmp(w.total) != 0 || got.largest != w.largest ||
+				got.cacheHits != w.cacheHits || got.cacheLookups != w.cacheLookups {
+				t.Fatalf("expected %v, got %v", w, got)
+			}
+			i++
+		}
+	})
+}
+
+func TestWriteBlinkStatsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	last, err := WriteBlinkStatsCSV(&buf, PuzzleRules.StreamBlinks(nil, SliceToStoneCounts([]uint64{0}), 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 0 -> 1 -> 2024 -> 20 24 -> 2 0 2 4 -> 4048 1 4048 8096, the mutation of 0 is in the table
+	want := strings.Join([]string{
+		"blink,distinct,total,largest,cache_hits,cache_lookups,cache_hit_rate",
+		"1,1,1,1,0,1,0.0000",
+		"2,1,1,2024,0,1,0.0000",
+		"3,2,2,24,0,1,0.0000",
+		"4,3,4,4,0,2,0.0000",
+		"5,3,4,8096,1,3,0.3333",
+	}, "\n") + "\n"
+	if buf.String() != want {
+		t.Fatalf("expected\n%v\ngot\n%v", want, buf.String())
+	}
+	if last.blink != 5 {
+		t.Fatalf("expected the last blink to be 5, got %d", last.blink)
+	}
+}
+
+func TestWriteBlinkStatsCSVNoBlink(t *testing.T) {
+	var buf bytes.Buffer
+	last, err := WriteBlinkStatsCSV(&buf, PuzzleRules.StreamBlinks(nil, SliceToStoneCounts([]uint64{0}), 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "blink,distinct,total,largest,cache_hits,cache_lookups,cache_hit_rate\n"
+	if buf.String() != want {
+		t.Fatalf("expected\n%v\ngot\n%v", want, buf.String())
+	}
+	if last.blink != 0 || last.total != nil {
+		t.Fatalf("expected the zero stats, got %+v", last)
+	}
+
+	if _, err := WriteBlinkStatsCSV(failingWriter{}, PuzzleRules.StreamBlinks(nil, SliceToStoneCounts([]uint64{0}), 0)); err == nil {
+		t.Fatal("expected the write error of the header")
+	}
+}
+
+func TestStreamBlinksDone(t *testing.T) {
+	done := make(chan struct{})
+	stream := StreamBlinks(done, SliceToStoneCounts([]uint64{125, 17}), 1000)
+	<-stream
+	close(done)
+
+	// the stream is closed without going through the 1000 blinks
+	received := 1
+	for range stream {
+		received++
+	}
+	if received == 1000 {
+		t.Fatalf("expected the stream to stop early")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteBlinkStatsCSVError(t *testing.T) {
+	done := make(chan struct{})
+	stream := PuzzleRules.StreamBlinks(done, SliceToStoneCounts([]uint64{0}), 1000)
+	if _, err := WriteBlinkStatsCSV(failingWriter{}, stream); err == nil {
+		t.Fatal("expected the write error")
+	}
+
+	close(done)
+	for range stream {
+	}
+}
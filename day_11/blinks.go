@@ -2,7 +2,12 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -13,63 +18,40 @@ type MutationResult struct {
 	newStoneNumbers []uint64
 }
 
-// this table saves the mutation result of any number seen so far
-var mutationTable = map[uint64]MutationResult{}
-
+// the simulator of the puzzle's rules, its cache saves the mutation result of any number seen so far
+var puzzleSimulator = NewSimulator(PuzzleRules, 0)
+
+// the puzzle's rules:
+// If the stone is engraved with the number 0, it is replaced by a stone engraved with the number 1.
+// If the stone is engraved with a number that has an even number of digits, it is replaced by two stones.
+// The left half of the digits are engraved on the new left stone, and the right half of the digits are engraved on the new right stone.
+// (The new numbers don't keep extra leading zeroes: 1000 would become stones 10 and 0.)
+// If none of the other rules apply, the stone is replaced by a new stone; the old stone's number multiplied by 2024 is engraved on the new stone.
+// The multiplication only overflows for stones of 17 digits or more, which the puzzle's stones never reach: it panics
+// on ErrOverflow, use PuzzleRules.Apply to get the error instead
 func StoneMutation(number uint64) []uint64 {
+	return mustMutate(PuzzleRules.Apply(number))
+}
 
-	// If the stone is engraved with the number 0, it is replaced by a stone engraved with the number 1.
-	if number == 0 {
-		return []uint64{1}
-	}
-
-	// If the stone is engraved with a number that has an even number of digits, it is replaced by two stones.
-	// The left half of the digits are engraved on the new left stone, and the right half of the digits are engraved on the new right stone.
-	// (The new numbers don't keep extra leading zeroes: 1000 would become stones 10 and 0.)
-	numberStr := fmt.Sprintf("%d", number)
-	if len(numberStr)%2 == 0 {
-		leftStone, _ := strconv.Atoi(numberStr[:len(numberStr)/2])
-		ringStone, _ := strconv.Atoi(numberStr[len(numberStr)/2:])
-		return []uint64{uint64(leftStone), uint64(ringStone)}
+func mustMutate[T any](result T, err error) T {
+	if err != nil {
+		panic(err)
 	}
-
-	// If none of the other rules apply, the stone is replaced by a new stone; the old stone's number multiplied by 2024 is engraved on the new stone.
-	return []uint64{number * 2024}
+	return result
 }
 
 // mutate a list of stones to a new list of stones
 func MutateStones(stones []uint64) (result []uint64) {
 	for _, stone := range stones {
-		// test if the stone mutation is already in the table
-		_, ok := mutationTable[stone]
-		if !ok {
-			mutationTable[stone] = MutationResult{stone, StoneMutation(stone)}
-		}
-		result = append(result, mutationTable[stone].newStoneNumbers...)
+		newStones, _, err := puzzleSimulator.cache.Mutate(stone, PuzzleRules.Apply)
+		result = append(result, mustMutate(newStones, err)...)
 	}
 	return
 }
 
 // input and out are maps of stone numbers and their count
 func MutateStoneMap(stones map[uint64]uint64) (result map[uint64]uint64) {
-	result = make(map[uint64]uint64)
-	for stone, count := range stones {
-		// the mutation and caching part
-		if _, ok := mutationTable[stone]; !ok {
-			mutationTable[stone] = MutationResult{stone, StoneMutation(stone)}
-		}
-
-		// the saving part
-		for _, newStone := range mutationTable[stone].newStoneNumbers {
-			if _, ok := result[newStone]; !ok {
-				result[newStone] = count
-			} else {
-				result[newStone] += count
-			}
-		}
-	}
-
-	return
+	return mustMutate(puzzleSimulator.MutateStoneMap(stones))
 }
 
 func SliceToMap(stones []uint64) (result map[uint64]uint64) {
@@ -89,11 +71,14 @@ func GetNumberOfStonesAfterMutation(stoneMap map[uint64]uint64, numberOfBlinks i
 		stoneMap = MutateStoneMap(stoneMap)
 	}
 
+	return countStones(stoneMap)
+}
+
+func countStones(stoneMap map[uint64]uint64) uint64 {
 	stoneCounts := uint64(0)
 	for _, count := range stoneMap {
 		stoneCounts += count
 	}
-
 	return stoneCounts
 }
 
@@ -106,11 +91,89 @@ func main() {
 		stoneInts[i] = uint64(x)
 	}
 
-	stoneIntMap := SliceToMap(stoneInts)
+	rulesFile := flag.String("rules", "", "read the rules from the file instead of using the puzzle's, see ParseRules for the format")
+	numberOfBlinks := flag.Int("blinks", 75, "the number of blinks")
+	statsFile := flag.String("stats", "", "write the statistics of each blink to the file, as CSV")
+	cacheSize := flag.Int("cache-size", 0, "the most mutations to cache, 0 for no bound")
+	cacheFile := flag.String("cache", "", "load the mutation cache from the file if it exists, and save it there after blinking")
+	flag.Parse()
+
+	rules := PuzzleRules
+	if *rulesFile != "" {
+		var err error
+		if rules, err = LoadRules(*rulesFile); err != nil {
+			fmt.Println("Error reading the rules:", err)
+			return
+		}
+		fmt.Printf("rules:\n%v\n", rules)
+	}
+	simulator := NewSimulator(rules, *cacheSize)
+
+	if *cacheFile != "" {
+		if err := loadCacheFile(simulator, *cacheFile); err != nil {
+			fmt.Println("Error loading the cache:", err)
+			return
+		}
+		fmt.Println("mutations in the cache:", simulator.Cache().Len())
+	}
+
+	statsWriter := io.Discard
+	if *statsFile != "" {
+		file, err := os.Create(*statsFile)
+		if err != nil {
+			fmt.Println("Error creating the statistics file:", err)
+			return
+		}
+		defer file.Close()
+		statsWriter = file
+	}
+
+	// stop the blinking if main returns before the end of the stream
+	done := make(chan struct{})
+	defer close(done)
 
-	numberOfBlinks := 75
 	start := time.Now()
-	numberOfStones := GetNumberOfStonesAfterMutation(stoneIntMap, numberOfBlinks)
-	fmt.Println("after", numberOfBlinks, " blinks, stoneCounts:", numberOfStones, "Elapsed time:", time.Since(start))
+	stoneCounts := SliceToStoneCounts(stoneInts)
+	last, err := WriteBlinkStatsCSV(statsWriter, simulator.StreamBlinks(done, stoneCounts, *numberOfBlinks))
+	if errors.Is(err, ErrOverflow) {
+		fmt.Println("Error blinking:", err)
+		return
+	} else if err != nil {
+		fmt.Println("Error writing the statistics:", err)
+		return
+	}
+	if last.total == nil {
+		// no blink ran, the stones are the ones we started with
+		last.total = stoneCounts.Total()
+	}
+	fmt.Println("after", last.blink, " blinks, stoneCounts:", last.total, "Elapsed time:", time.Since(start))
 
+	if *cacheFile != "" {
+		if err := saveCacheFile(simulator, *cacheFile); err != nil {
+			fmt.Println("Error saving the cache:", err)
+		}
+	}
+}
+
+func loadCacheFile(simulator *Simulator, path string) error {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+	return simulator.LoadCache(file)
+}
+
+func saveCacheFile(simulator *Simulator, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := simulator.SaveCache(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
 }
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// a test on the number of a stone
+type Predicate struct {
+	name string
+	test func(number uint64) bool
+}
+
+// a stone number doesn't fit in a uint64 after a transformation
+var ErrOverflow = errors.New("stone number overflows a uint64")
+
+// what a stone becomes, or ErrOverflow when a new number doesn't fit
+type Transformation struct {
+	name  string
+	apply func(number uint64) ([]uint64, error)
+}
+
+// a stone matching the predicate is replaced by the stones of the transformation
+type RewriteRule struct {
+	when Predicate
+	then Transformation
+}
+
+func (r RewriteRule) String() string {
+	return r.when.name + " -> " + r.then.name
+}
+
+// rules are tried in order, the first one whose predicate matches rewrites the stone.
+// A stone matching no rule stays as it is
+type RuleSet []RewriteRule
+
+func (rs RuleSet) Apply(number uint64) ([]uint64, error) {
+	for _, rule := range rs {
+		if rule.when.test(number) {
+			return rule.then.apply(number)
+		}
+	}
+	return []uint64{number}, nil
+}
+
+func (rs RuleSet) String() string {
+	lines := make([]string, len(rs))
+	for i, rule := range rs {
+		lines[i] = rule.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func digitCount(number uint64) int {
+	count := 1
+	for number >= 10 {
+		number /= 10
+		count++
+	}
+	return count
+}
+
+func pow10(n int) uint64 {
+	result := uint64(1)
+	for range n {
+		result *= 10
+	}
+	return result
+}
+
+func Always() Predicate {
+	return Predicate{"always", func(uint64) bool { return true }}
+}
+
+func ValueIs(value uint64) Predicate {
+	return Predicate{fmt.Sprintf("value %d", value), func(number uint64) bool { return number == value }}
+}
+
+func DivisibleBy(divisor uint64) Predicate {
+	return Predicate{fmt.Sprintf("divisible %d", divisor), func(number uint64) bool { return number%divisor == 0 }}
+}
+
+func Even() Predicate {
+	return Predicate{"even", func(number uint64) bool { return number%2 == 0 }}
+}
+
+func Odd() Predicate {
+	return Predicate{"odd", func(number uint64) bool { return number%2 == 1 }}
+}
+
+func DigitCountIs(count int) Predicate {
+	return Predicate{fmt.Sprintf("digits %d", count), func(number uint64) bool { return digitCount(number) == count }}
+}
+
+// the number of digits is a multiple of k, e.g. "digits even" is DigitCountMultipleOf(2)
+func DigitCountMultipleOf(k int) Predicate {
+	name := fmt.Sprintf("digits multiple %d", k)
+	if k == 2 {
+		name = "digits even"
+	}
+	return Predicate{name, func(number uint64) bool { return digitCount(number)%k == 0 }}
+}
+
+func DigitCountOdd() Predicate {
+	return Predicate{"digits odd", func(number uint64) bool { return digitCount(number)%2 == 1 }}
+}
+
+// the stone is replaced by stones with the given numbers
+func Set(numbers ...uint64) Transformation {
+	name := "set"
+	for _, number := range numbers {
+		name += fmt.Sprintf(" %d", number)
+	}
+	return Transformation{name, func(uint64) ([]uint64, error) { return append([]uint64(nil), numbers...), nil }}
+}
+
+func Multiply(factor uint64) Transformation {
+	return Transformation{fmt.Sprintf("multiply %d", factor), func(number uint64) ([]uint64, error) {
+		high, product := bits.Mul64(number, factor)
+		if high != 0 {
+			return nil, fmt.Errorf("%w: %d * %d", ErrOverflow, number, factor)
+		}
+		return []uint64{product}, nil
+	}}
+}
+
+func Add(term uint64) Transformation {
+	return Transformation{fmt.Sprintf("add %d", term), func(number uint64) ([]uint64, error) {
+		sum, carry := bits.Add64(number, term, 0)
+		if carry != 0 {
+			return nil, fmt.Errorf("%w: %d + %d", ErrOverflow, number, term)
+		}
+		return []uint64{sum}, nil
+	}}
+}
+
+func Keep() Transformation {
+	return Transformation{"keep", func(number uint64) ([]uint64, error) { return []uint64{number}, nil }}
+}
+
+// split the digits of the stone into parts stones of the same number of digits, the first stone takes the digits
+// left over. The new numbers don't keep leading zeroes: 1000 split in 2 becomes 10 and 0
+func Split(parts int) Transformation {
+	return Transformation{fmt.Sprintf("split %d", parts), func(number uint64) ([]uint64, error) {
+		digits := digitCount(number)
+		partDigits := digits / parts
+		if partDigits == 0 {
+			return []uint64{number}, nil
+		}
+
+		stones := make([]uint64, parts)
+		divisor := pow10(partDigits)
+		for i := parts - 1; i > 0; i-- {
+			stones[i] = number % divisor
+			number /= divisor
+		}
+		stones[0] = number
+		return stones, nil
+	}}
+}
+
+// the rules of the puzzle
+var PuzzleRules = RuleSet{
+	{ValueIs(0), Set(1)},
+	{DigitCountMultipleOf(2), Split(2)},
+	{Always(), Multiply(2024)},
+}
+
+func parseNumbers(fields []string) ([]uint64, error) {
+	numbers := make([]uint64, len(fields))
+	for i, field := range fields {
+		number, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = number
+	}
+	return numbers, nil
+}
+
+// the single number after the keyword
+func parseArgument(fields []string) (uint64, bool) {
+	if len(fields) != 2 {
+		return 0, false
+	}
+	number, err := strconv.ParseUint(fields[1], 10, 64)
+	return number, err == nil
+}
+
+func parsePredicate(fields []string) (Predicate, error) {
+	line := strings.Join(fields, " ")
+	switch line {
+	case "always":
+		return Always(), nil
+	case "even":
+		return Even(), nil
+	case "odd":
+		return Odd(), nil
+	case "digits even":
+		return DigitCountMultipleOf(2), nil
+	case "digits odd":
+		return DigitCountOdd(), nil
+	}
+
+	if strings.HasPrefix(line, "digits multiple ") {
+		if k, ok := parseArgument(fields[1:]); ok && k > 0 {
+			return DigitCountMultipleOf(int(k)), nil
+		}
+	} else if number, ok := parseArgument(fields); ok {
+		switch {
+		case fields[0] == "value":
+			return ValueIs(number), nil
+		case fields[0] == "divisible" && number > 0:
+			return DivisibleBy(number), nil
+		case fields[0] == "digits":
+			return DigitCountIs(int(number)), nil
+		}
+	}
+	return Predicate{}, fmt.Errorf("unknown predicate %q", line)
+}
+
+func parseTransformation(fields []string) (Transformation, error) {
+	line := strings.Join(fields, " ")
+	if line == "keep" {
+		return Keep(), nil
+	}
+	if len(fields) >= 2 && fields[0] == "set" {
+		if numbers, err := parseNumbers(fields[1:]); err == nil {
+			return Set(numbers...), nil
+		}
+	}
+
+	if number, ok := parseArgument(fields); ok {
+		switch {
+		case fields[0] == "multiply":
+			return Multiply(number), nil
+		case fields[0] == "add":
+			return Add(number), nil
+		case fields[0] == "split" && number > 0:
+			return Split(int(number)), nil
+		}
+	}
+	return Transformation{}, fmt.Errorf("unknown transformation %q", line)
+}
+
+// read rules, one per line, as "<predicate> -> <transformation>". Blank lines and lines starting with # are ignored.
+//
+//	predicates:      always, value N, even, odd, divisible N, digits N, digits even, digits odd, digits multiple N
+//	transformations: set N..., multiply N, add N, split N, keep
+func ParseRules(r io.Reader) (RuleSet, error) {
+	rules := RuleSet{}
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		when, then, found := strings.Cut(line, "->")
+		if !found {
+			return nil, fmt.Errorf("line %d: missing ->", lineNumber)
+		}
+		predicate, err := parsePredicate(strings.Fields(when))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		transformation, err := parseTransformation(strings.Fields(then))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		rules = append(rules, RewriteRule{predicate, transformation})
+	}
+	return rules, scanner.Err()
+}
+
+func LoadRules(path string) (RuleSet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseRules(file)
+}
+
+// the number of stones after blinking with the rules, with a cache of their own
+func (rs RuleSet) GetNumberOfStonesAfterMutation(stoneMap map[uint64]uint64, numberOfBlinks int) (uint64, error) {
+	return NewSimulator(rs, 0).GetNumberOfStonesAfterMutation(stoneMap, numberOfBlinks)
+}
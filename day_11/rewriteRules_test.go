@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		parts    int
+		number   uint64
+		expected []uint64
+	}{
+		{"halves", 2, 253000, []uint64{253, 0}},
+		{"leading zeroes", 2, 1000, []uint64{10, 0}},
+		{"thirds", 3, 123456, []uint64{12, 34, 56}},
+		{"left over digits", 3, 1234567, []uint64{123, 45, 67}},
+		{"too few digits", 3, 12, []uint64{12}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Split(tt.parts).apply(tt.number)
+			if err != nil || !slices.Equal(got, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	tests := []struct {
+		name           string
+		transformation Transformation
+		number         uint64
+		expected       uint64
+		overflows      bool
+	}{
+		{"multiply", Multiply(2024), 1 << 40, 2024 << 40, false},
+		{"multiply overflows", Multiply(2024), math.MaxUint64 / 2000, 0, true},
+		{"multiply by 0", Multiply(0), math.MaxUint64, 0, false},
+		{"add", Add(1), math.MaxUint64 - 1, math.MaxUint64, false},
+		{"add overflows", Add(2), math.MaxUint64 - 1, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.transformation.apply(tt.number)
+			if tt.overflows {
+				if !errors.Is(err, ErrOverflow) {
+					t.Fatalf("expected an overflow, got %v %v", got, err)
+				}
+				return
+			}
+			if err != nil || !slices.Equal(got, []uint64{tt.expected}) {
+				t.Fatalf("expected %v, got %v %v", tt.expected, got, err)
+			}
+		})
+	}
+
+	t.Run("blinking", func(t *testing.T) {
+		rules := RuleSet{{Always(), Multiply(1000)}}
+		// 7 followed by 18 zeroes fits in a uint64, followed by 21 it doesn't
+		if _, err := rules.GetNumberOfStonesAfterMutation(SliceToMap([]uint64{7}), 6); err != nil {
+			t.Fatalf("expected no overflow after 6 blinks, got %v", err)
+		}
+		_, err := rules.GetNumberOfStonesAfterMutation(SliceToMap([]uint64{7}), 7)
+		if !errors.Is(err, ErrOverflow) {
+			t.Fatalf("expected an overflow, got %v", err)
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		rules := RuleSet{{Always(), Add(math.MaxUint64 / 4)}}
+		var buf bytes.Buffer
+		last, err := WriteBlinkStatsCSV(&buf, rules.StreamBlinks(nil, SliceToStoneCounts([]uint64{0}), 10))
+		if !errors.Is(err, ErrOverflow) {
+			t.Fatalf("expected an overflow, got %v", err)
+		}
+		if last.blink != 4 {
+			t.Fatalf("expected the overflow after blink 4, got %d", last.blink)
+		}
+	})
+}
+
+func TestParseRules(t *testing.T) {
+	t.Run("puzzle rules", func(t *testing.T) {
+		config := `
+# the rules of the puzzle
+value 0 -> set 1
+digits even -> split 2
+always -> multiply 2024
+`
+		rules, err := ParseRules(strings.NewReader(config))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rules.String() != PuzzleRules.String() {
+			t.Fatalf("expected %v, got %v", PuzzleRules, rules)
+		}
+
+		want := uint64(55312)
+		got, err := rules.GetNumberOfStonesAfterMutation(SliceToMap([]uint64{125, 17}), 25)
+		if err != nil || got != want {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("variant", func(t *testing.T) {
+		config := `
+divisible 5 -> set 7 7
+digits multiple 3 -> split 3
+odd -> add 1
+`
+		rules, err := ParseRules(strings.NewReader(config))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			number   uint64
+			expected []uint64
+		}{
+			{10, []uint64{7, 7}},
+			{123, []uint64{1, 2, 3}},
+			{11, []uint64{12}},
+			{12, []uint64{12}}, // no rule matches
+		}
+		for _, tt := range tests {
+			if got, err := rules.Apply(tt.number); err != nil || !slices.Equal(got, tt.expected) {
+				t.Fatalf("%d: expected %v, got %v", tt.number, tt.expected, got)
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, config := range []string{"value 0 set 1", "prime -> set 1", "value 0 -> split 0", "divisible 0 -> keep", "digits x -> keep"} {
+			if _, err := ParseRules(strings.NewReader(config)); err == nil {
+				t.Fatalf("%q: expected an error", config)
+			}
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"container/list"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// the mutation results of the stone numbers seen so far, safe for concurrent use. With a capacity, the least recently
+// used result is evicted when the cache is full
+type MutationCache struct {
+	mu       sync.Mutex
+	capacity int                      // 0 for no bound
+	entries  map[uint64]*list.Element // the elements of order, by stone number
+	order    *list.List               // of MutationResult, most recently used first
+}
+
+func NewMutationCache(capacity int) *MutationCache {
+	return &MutationCache{capacity: capacity, entries: make(map[uint64]*list.Element), order: list.New()}
+}
+
+func (c *MutationCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *MutationCache) get(stone uint64) ([]uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[stone]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(MutationResult).newStoneNumbers, true
+}
+
+func (c *MutationCache) put(result MutationResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[result.stoneNumber]; ok {
+		// another simulation got there first
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[result.stoneNumber] = c.order.PushFront(result)
+	if c.capacity > 0 && c.order.Len() > c.capacity {
+		oldest := c.order.Remove(c.order.Back()).(MutationResult)
+		delete(c.entries, oldest.stoneNumber)
+	}
+}
+
+// the new stones of the stone, from the cache or from the mutation, and whether they were in the cache.
+// A mutation which fails is not cached
+func (c *MutationCache) Mutate(stone uint64, mutation func(uint64) ([]uint64, error)) ([]uint64, bool, error) {
+	if newStones, ok := c.get(stone); ok {
+		return newStones, true, nil
+	}
+	// mutate without holding the lock, other simulations can use the cache meanwhile
+	newStones, err := mutation(stone)
+	if err != nil {
+		return nil, false, err
+	}
+	c.put(MutationResult{stone, newStones})
+	return newStones, false, nil
+}
+
+// blinks with a set of rules, and a cache of the mutations of these rules
+type Simulator struct {
+	rules RuleSet
+	cache *MutationCache
+}
+
+// a simulator with the rules, caching up to cacheSize mutations, or all of them if cacheSize is 0
+func NewSimulator(rules RuleSet, cacheSize int) *Simulator {
+	return &Simulator{rules, NewMutationCache(cacheSize)}
+}
+
+func (s *Simulator) Cache() *MutationCache {
+	return s.cache
+}
+
+// input and out are maps of stone numbers and their count
+func (s *Simulator) MutateStoneMap(stones map[uint64]uint64) (map[uint64]uint64, error) {
+	result := make(map[uint64]uint64)
+	for stone, count := range stones {
+		newStones, _, err := s.cache.Mutate(stone, s.rules.Apply)
+		if err != nil {
+			return nil, err
+		}
+		for _, newStone := range newStones {
+			result[newStone] += count
+		}
+	}
+	return result, nil
+}
+
+func (s *Simulator) GetNumberOfStonesAfterMutation(stoneMap map[uint64]uint64, numberOfBlinks int) (uint64, error) {
+	for i := 1; i <= numberOfBlinks; i++ {
+		var err error
+		if stoneMap, err = s.MutateStoneMap(stoneMap); err != nil {
+			return 0, fmt.Errorf("blink %d: %w", i, err)
+		}
+	}
+	return countStones(stoneMap), nil
+}
+
+// the first line of a saved cache, followed by the rules separated by ;
+const cacheHeader = "# rules: "
+
+// write the cache as text: a header with the rules, then one line per stone number "stone: new stones...", least
+// recently used first
+func (s *Simulator) SaveCache(w io.Writer) error {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, "%s%s\n", cacheHeader, strings.ReplaceAll(s.rules.String(), "\n", "; "))
+	for element := s.cache.order.Back(); element != nil; element = element.Prev() {
+		result := element.Value.(MutationResult)
+		fmt.Fprintf(writer, "%d:", result.stoneNumber)
+		for _, newStone := range result.newStoneNumbers {
+			fmt.Fprintf(writer, " %d", newStone)
+		}
+		fmt.Fprintln(writer)
+	}
+	return writer.Flush()
+}
+
+// add the results of a cache written by SaveCache to the cache. The cache must have been saved with the same rules
+func (s *Simulator) LoadCache(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return io.ErrUnexpectedEOF
+	}
+	rules := strings.ReplaceAll(s.rules.String(), "\n", "; ")
+	if header := scanner.Text(); header != cacheHeader+rules {
+		return fmt.Errorf("the cache was saved with other rules: %q", strings.TrimPrefix(header, cacheHeader))
+	}
+
+	for lineNumber := 2; scanner.Scan(); lineNumber++ {
+		stoneField, newStoneFields, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			return fmt.Errorf("line %d: missing :", lineNumber)
+		}
+		stone, err := strconv.ParseUint(stoneField, 10, 64)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		newStones, err := parseNumbers(strings.Fields(newStoneFields))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		s.cache.put(MutationResult{stone, newStones})
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMutationCache(t *testing.T) {
+	cache := NewMutationCache(2)
+	for _, stone := range []uint64{0, 1, 0, 2} {
+		cache.Mutate(stone, PuzzleRules.Apply)
+	}
+
+	// 1 is the least recently used, it was evicted when 2 came in
+	if cache.Len() != 2 {
+		t.Fatalf("expected 2 mutations in the cache, got %d", cache.Len())
+	}
+	for stone, want := range map[uint64]bool{0: true, 1: false, 2: true} {
+		if _, got := cache.get(stone); got != want {
+			t.Fatalf("%d: expected in the cache %v, got %v", stone, want, got)
+		}
+	}
+}
+
+func TestSimulator(t *testing.T) {
+	stones := SliceToMap([]uint64{125, 17})
+
+	t.Run("bounded cache", func(t *testing.T) {
+		simulator := NewSimulator(PuzzleRules, 10)
+		want := uint64(55312)
+		got, err := simulator.GetNumberOfStonesAfterMutation(stones, 25)
+		if err != nil || got != want {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+		if simulator.Cache().Len() > 10 {
+			t.Fatalf("expected at most 10 mutations in the cache, got %d", simulator.Cache().Len())
+		}
+	})
+
+	t.Run("concurrent simulations", func(t *testing.T) {
+		simulator := NewSimulator(PuzzleRules, 50)
+		var wg sync.WaitGroup
+		results := make([]uint64, 8)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = simulator.GetNumberOfStonesAfterMutation(stones, 25)
+			}()
+		}
+		wg.Wait()
+
+		for _, got := range results {
+			if got != 55312 {
+				t.Fatalf("expected 55312, got %v", results)
+			}
+		}
+	})
+
+	t.Run("save and load", func(t *testing.T) {
+		simulator := NewSimulator(PuzzleRules, 0)
+		simulator.GetNumberOfStonesAfterMutation(stones, 6)
+
+		var saved bytes.Buffer
+		if err := simulator.SaveCache(&saved); err != nil {
+			t.Fatal(err)
+		}
+
+		reloaded := NewSimulator(PuzzleRules, 0)
+		if err := reloaded.LoadCache(bytes.NewReader(saved.Bytes())); err != nil {
+			t.Fatal(err)
+		}
+		if reloaded.Cache().Len() != simulator.Cache().Len() {
+			t.Fatalf("expected %d mutations, got %d", simulator.Cache().Len(), reloaded.Cache().Len())
+		}
+
+		// saved again, the cache is the same, in the same order
+		var resaved bytes.Buffer
+		reloaded.SaveCache(&resaved)
+		if resaved.String() != saved.String() {
+			t.Fatalf("expected\n%v\ngot\n%v", saved.String(), resaved.String())
+		}
+
+		// the mutations of the same 6 blinks are all in the cache
+		misses := 0
+		for stats := range reloaded.StreamBlinks(nil, SliceToStoneCounts([]uint64{125, 17}), 6) {
+			misses += stats.cacheLookups - stats.cacheHits
+		}
+		if misses != 0 {
+			t.Fatalf("expected no cache misses, got %d", misses)
+		}
+	})
+
+	t.Run("load errors", func(t *testing.T) {
+		simulator := NewSimulator(PuzzleRules, 0)
+		var saved bytes.Buffer
+		simulator.SaveCache(&saved)
+
+		otherRules := NewSimulator(RuleSet{{Always(), Keep()}}, 0)
+		if err := otherRules.LoadCache(bytes.NewReader(saved.Bytes())); err == nil {
+			t.Fatal("expected an error loading a cache saved with other rules")
+		}
+		if err := simulator.LoadCache(strings.NewReader(saved.String() + "12 24\n")); err == nil {
+			t.Fatal("expected an error loading a line without :")
+		}
+		if err := simulator.LoadCache(strings.NewReader("")); err == nil {
+			t.Fatal("expected an error loading an empty cache")
+		}
+	})
+}
@@ -2,6 +2,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io"
 	"os"
@@ -12,7 +13,7 @@ import (
 func parseInput(data string) [][]rune {
 	var gardenMap [][]rune
 
-	lines := strings.Split(data, "\n")
+	lines := strings.Split(strings.TrimRight(data, "\n"), "\n")
 	for _, line := range lines {
 		gardenMap = append(gardenMap, []rune(line))
 	}
@@ -28,6 +29,7 @@ type Location struct {
 type LocationGroup struct {
 	groupID   string
 	locations []Location
+	region    Region // the region of the plots, with its area, perimeter and sides
 }
 
 // Directions for moving up, down, left, right
@@ -38,196 +40,75 @@ var directions = [][2]int{
 
 // build a groupID based on the location and gardenMap
 func BuildGroupID(gardenMap [][]rune, location Location) string {
-	return fmt.Sprintf("%c_%d_%d", gardenMap[location.x][location.y], location.x, location.y)
+	return formatGroupID(gardenMap[location.x][location.y], location)
+}
+
+// the ID of a group or region: its plant and the coordinates of its first plot, e.g. A_0_0
+func formatGroupID(plant rune, location Location) string {
+	return fmt.Sprintf("%c_%d_%d", plant, location.x, location.y)
 }
 
 // find the location groups in the garden. return a map of groupID to LocationGroup
 // groupID is a string created by the coordinates of the first location in the group
 func FindGroups(gardenMap [][]rune) map[string]*LocationGroup {
+	labels := LabelRegions(gardenMap)
+	locations := labels.Locations()
 
 	groups := make(map[string]*LocationGroup)
-
-	visitedPlots := make(map[Location]bool)
-
-	queue := []Location{}
-
-	for len(visitedPlots) < len(gardenMap)*len(gardenMap[0]) {
-
-		// find the first unvisited plot
-		firstUnvisitedPlot := findUnvisitedLocation(gardenMap, visitedPlots)
-
-		// find the groupID of the first unvisited plot, add it to the groups map
-		groupID := BuildGroupID(gardenMap, firstUnvisitedPlot)
-		groups[groupID] = &LocationGroup{groupID, []Location{firstUnvisitedPlot}}
-		visitedPlots[firstUnvisitedPlot] = true
-
-		fmt.Println("firstUnvisitedPlot", firstUnvisitedPlot, "groupID", groupID)
-
-		// do a BFS starting from the firstUnvisitedPlot, add all the connected plots to the group
-		queue = append(queue, firstUnvisitedPlot)
-		for len(queue) > 0 {
-			// pop the first element from the queue
-			currentLocation := queue[0]
-			queue = queue[1:]
-
-			// check the neighbors of the current location
-			for _, dir := range directions {
-				newLocation := Location{currentLocation.x + dir[0], currentLocation.y + dir[1]}
-				if newLocation.x >= 0 && newLocation.x < len(gardenMap) && newLocation.y >= 0 && newLocation.y < len(gardenMap[0]) {
-
-					// if this neighbor has not been visited before, and it has the same plant as the current location, add it to the group
-					if _, ok := visitedPlots[newLocation]; !ok && gardenMap[newLocation.x][newLocation.y] == gardenMap[currentLocation.x][currentLocation.y] {
-						// add the new location to the group
-						groups[groupID].locations = append(groups[groupID].locations, newLocation)
-						// mark the new location as visited
-						visitedPlots[newLocation] = true
-						// add the new location to the queue for further exploration
-						queue = append(queue, newLocation)
-					}
-
-				}
-			}
-		}
-
+	for _, region := range labels.Regions() {
+		groupID := BuildGroupID(gardenMap, region.start)
+		groups[groupID] = &LocationGroup{groupID, locations[region.label], region}
 	}
-
 	return groups
 }
 
-func findUnvisitedLocation(gardenMap [][]rune, visitedPlots map[Location]bool) (firstUnvisitedPlot Location) {
-	for i := 0; i < len(gardenMap); i++ {
-		for j := 0; j < len(gardenMap[0]); j++ {
-			if _, ok := visitedPlots[Location{i, j}]; !ok {
-				firstUnvisitedPlot = Location{i, j}
-				return
-			}
-		}
+func plotSet(locations []Location) map[Location]bool {
+	plots := make(map[Location]bool, len(locations))
+	for _, location := range locations {
+		plots[location] = true
 	}
-	return
+	return plots
 }
 
+// the area and perimeter of the group's plots. Plots outside the garden are never in the group, so the garden map is
+// only there for the callers. FindGroups measured its groups already, see Region
 func CalculateRegionCost(gardenMap [][]rune, region *LocationGroup) (area int, perimeter int) {
-	area = len(region.locations)
-
-	perimeter = 4 * area
-	for _, location := range region.locations {
-
+	plots := plotSet(region.locations)
+	for location := range plots {
 		for _, dir := range directions {
-			neighbor := Location{location.x + dir[0], location.y + dir[1]}
-			if neighbor.x >= 0 && neighbor.x < len(gardenMap) && neighbor.y >= 0 || neighbor.y < len(gardenMap[0]) {
-				if slices.Contains(region.locations, neighbor) {
-					perimeter = perimeter - 1
-				}
+			if !plots[Location{location.x + dir[0], location.y + dir[1]}] {
+				perimeter++
 			}
 		}
-
 	}
-
-	return
+	return len(plots), perimeter
 }
 
-func side_count_update(current_type bool) int {
-	if current_type {
-		return 0
-	}
-	return 1
-}
-
-// calculate the horizontal sides of the region
-func CalculateHorizontalSides(gardenMap [][]rune, region *LocationGroup) int {
-
-	// a horizontal side is created when the plot is in the region and the plot above it is not in the region, call it type_1_side, (or vice versa call it type_2_side)
-	// side ends when the plot and the one above it is in the same region
-
-	// plots outside the garden boundary are treated as not	in the region
-
-	total_sides := 0
-
-	for row := 0; row <= len(gardenMap); row++ {
-		type_1_side := 0
-		type_2_side := 0
-		type_1_side_ongoing := false
-		type_2_side_ongoing := false
-
-		for col := 0; col < len(gardenMap[0]); col++ {
-			currentPlot := Location{row, col}
-			upperPlot := Location{row - 1, col}
-
-			// check for type_1_side
-			if slices.Contains(region.locations, currentPlot) && !slices.Contains(region.locations, upperPlot) {
-				// we may or may not update the type_1_side count
-				type_1_side += side_count_update(type_1_side_ongoing)
-				type_1_side_ongoing = true
-				type_2_side_ongoing = false
-				continue
-			}
-
-			// check for type_2_side
-			if !slices.Contains(region.locations, currentPlot) && slices.Contains(region.locations, upperPlot) {
-				// we may or may not update the type_2_side count
-				type_2_side += side_count_update(type_2_side_ongoing)
-				type_2_side_ongoing = true
-				type_1_side_ongoing = false
-				continue
+// count the sides along one axis: an edge of a plot is on a side when the neighbor across it, in direction out, is not
+// in the group. The side starts at the plot unless the plot before it, in direction along, has the same edge
+func countSides(plots map[Location]bool, along Location, outs ...Location) int {
+	step := func(location, dir Location) Location { return Location{location.x + dir.x, location.y + dir.y} }
+
+	sides := 0
+	for location := range plots {
+		before := step(location, along)
+		for _, out := range outs {
+			if !plots[step(location, out)] && !(plots[before] && !plots[step(before, out)]) {
+				sides++
 			}
-
-			// if both plots are in the region or outside the region, we stop the ongoing side
-			type_1_side_ongoing = false
-			type_2_side_ongoing = false
 		}
-
-		// fmt.Println("row", row, "type_1_side", type_1_side, "type_2_side", type_2_side)
-
-		total_sides += type_1_side + type_2_side
 	}
+	return sides
+}
 
-	return total_sides
-
+// calculate the horizontal sides of the group: the sides above and below its plots, run from left to right
+func CalculateHorizontalSides(gardenMap [][]rune, region *LocationGroup) int {
+	return countSides(plotSet(region.locations), Location{0, -1}, Location{-1, 0}, Location{1, 0})
 }
 
+// calculate the vertical sides of the group: the sides left and right of its plots, run from top to bottom
 func CalculateVerticalSides(gardenMap [][]rune, region *LocationGroup) int {
-
-	// a vertical side is created when the plot is in the region and the plot to the left of it is not in the region, call it type_1_side, (or vice versa call it type_2_side)
-	// side ends when the plot and the one to the left of it is in the same region
-
-	// plots outside the garden boundary are treated as not	in the region
-
-	total_sides := 0
-
-	for col := 0; col <= len(gardenMap[0]); col++ {
-		type_1_side := 0
-		type_2_side := 0
-		type_1_side_ongoing := false
-		type_2_side_ongoing := false
-
-		for row := 0; row < len(gardenMap); row++ {
-			currentPlot := Location{row, col}
-			leftPlot := Location{row, col - 1}
-
-			// check for type_1_side
-			if slices.Contains(region.locations, currentPlot) && !slices.Contains(region.locations, leftPlot) {
-				type_1_side += side_count_update(type_1_side_ongoing)
-				type_1_side_ongoing = true
-				type_2_side_ongoing = false
-				continue
-			}
-
-			// check for type_2_side
-			if !slices.Contains(region.locations, currentPlot) && slices.Contains(region.locations, leftPlot) {
-				type_2_side += side_count_update(type_2_side_ongoing)
-				type_2_side_ongoing = true
-				type_1_side_ongoing = false
-				continue
-			}
-
-			type_1_side_ongoing = false
-			type_2_side_ongoing = false
-		}
-
-		total_sides += type_1_side + type_2_side
-	}
-
-	return total_sides
+	return countSides(plotSet(region.locations), Location{-1, 0}, Location{0, -1}, Location{0, 1})
 }
 
 func CalculateSides(gardenMap [][]rune, region *LocationGroup) int {
@@ -235,23 +116,13 @@ func CalculateSides(gardenMap [][]rune, region *LocationGroup) int {
 }
 
 func CalculatePricePart2(gardenMap [][]rune) int {
-	plotGroups := FindGroups(gardenMap)
-
-	totalCost := 0
-	for _, group := range plotGroups {
-		area := len(group.locations)
-		horizontalSides := CalculateHorizontalSides(gardenMap, group)
-		verticalSides := CalculateVerticalSides(gardenMap, group)
-
-		sides := horizontalSides + verticalSides
-		fmt.Println("group", group.groupID, "area", area, "sides", sides, "horizontal:", horizontalSides, "verticalSides", verticalSides)
-		totalCost += area * sides
-	}
-
-	return totalCost
+	return LabelRegions(gardenMap).DiscountPrice()
 }
 
 func main() {
+	svgFile := flag.String("svg", "", "write the regions to the file as SVG")
+	geoJSONFile := flag.String("geojson", "", "write the regions to the file as GeoJSON")
+	flag.Parse()
 
 	data, err := io.ReadAll(os.Stdin)
 	if err != nil {
@@ -262,19 +133,38 @@ func main() {
 	// read the input into a 2D array
 	gardenMap := parseInput(string(data))
 
-	// fmt.Println(gardenMap)
-
-	// plotGroups := FindGroups(gardenMap)
+	labels := LabelRegions(gardenMap)
+	fmt.Println("totalCost", labels.Price())
+	fmt.Println("totalCostPart2", labels.DiscountPrice())
 
-	// totalCost := 0
-	// for _, group := range plotGroups {
-	// 	area, perimeter := CalculateRegionCost(gardenMap, group)
-	// 	fmt.Println("area", area, "perimeter", perimeter)
-	// 	totalCost += area * perimeter
-	// }
-
-	// fmt.Println("totalCost", totalCost)
+	adjacency := labels.Adjacency()
+	// an empty garden has no regions to colour
+	colours := 0
+	if len(labels.Regions()) > 0 {
+		colours = slices.Max(adjacency.Colouring()) + 1
+	}
+	fmt.Println("regions", len(labels.Regions()), "enclosed by another", len(adjacency.Enclosures()), "map colours", colours)
 
-	fmt.Println("totalCostPart2", CalculatePricePart2(gardenMap))
+	if *svgFile != "" {
+		if err := writeFile(*svgFile, func(w io.Writer) error { return labels.WriteSVG(w, 10) }); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing the SVG: %v\n", err)
+		}
+	}
+	if *geoJSONFile != "" {
+		if err := writeFile(*geoJSONFile, labels.WriteGeoJSON); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing the GeoJSON: %v\n", err)
+		}
+	}
+}
 
+func writeFile(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
 }
@@ -0,0 +1,113 @@
+package main
+
+import "slices"
+
+// which regions touch, and along how many fences
+type RegionAdjacency struct {
+	labels     *RegionLabels
+	shared     []map[int]int // by label, the number of fences shared with each neighbor region
+	edgeFences []int         // by label, the number of fences on the edge of the garden
+}
+
+// build the adjacency graph of the regions, looking at each plot and the plots right of and below it once
+func (r *RegionLabels) Adjacency() RegionAdjacency {
+	a := RegionAdjacency{r, make([]map[int]int, len(r.regions)), make([]int, len(r.regions))}
+	for label := range a.shared {
+		a.shared[label] = make(map[int]int)
+	}
+
+	for x := 0; x < r.rows; x++ {
+		for y := 0; y < r.cols; y++ {
+			label := r.Label(x, y)
+			for _, neighbor := range []int{r.Label(x, y+1), r.Label(x+1, y)} {
+				if neighbor != -1 && neighbor != label {
+					a.shared[label][neighbor]++
+					a.shared[neighbor][label]++
+				}
+			}
+			for _, dir := range directions {
+				if !r.inside(x+dir[0], y+dir[1]) {
+					a.edgeFences[label]++
+				}
+			}
+		}
+	}
+	return a
+}
+
+// the labels of the regions touching the region, in label order
+func (a RegionAdjacency) Neighbors(label int) []int {
+	neighbors := make([]int, 0, len(a.shared[label]))
+	for neighbor := range a.shared[label] {
+		neighbors = append(neighbors, neighbor)
+	}
+	slices.Sort(neighbors)
+	return neighbors
+}
+
+// the number of fences between the two regions, 0 if they don't touch
+func (a RegionAdjacency) SharedFences(label, other int) int {
+	return a.shared[label][other]
+}
+
+// the regions whose outside is surrounded by a single other region, by the label of that region. The enclosed region
+// doesn't touch the edge of the garden, and any regions in its own holes don't count
+func (a RegionAdjacency) Enclosures() map[int]int {
+	enclosures := make(map[int]int)
+	for label := range a.shared {
+		if a.edgeFences[label] > 0 {
+			continue
+		}
+
+		outside := a.Neighbors(label)
+		if a.labels.regions[label].holes > 0 {
+			inside := a.labels.Enclosed(label)
+			outside = slices.DeleteFunc(outside, func(other int) bool {
+				_, found := slices.BinarySearch(inside, other)
+				return found
+			})
+		}
+		if len(outside) == 1 {
+			enclosures[label] = outside[0]
+		}
+	}
+	return enclosures
+}
+
+// colour the regions so that touching regions have different colours, with the DSatur heuristic: colour next the
+// region whose neighbors already have the most different colours, the one with the most neighbors on a tie, and give it
+// the smallest colour none of its neighbors has. The colours are 0, 1, 2... by label
+func (a RegionAdjacency) Colouring() []int {
+	colours := make([]int, len(a.shared))
+	for label := range colours {
+		colours[label] = -1
+	}
+	// the colours of the neighbors of each region
+	neighborColours := make([]map[int]bool, len(a.shared))
+	for label := range neighborColours {
+		neighborColours[label] = make(map[int]bool)
+	}
+
+	for range colours {
+		next := -1
+		for label, colour := range colours {
+			if colour != -1 {
+				continue
+			}
+			if next == -1 || len(neighborColours[label]) > len(neighborColours[next]) ||
+				len(neighborColours[label]) == len(neighborColours[next]) && len(a.shared[label]) > len(a.shared[next]) {
+				next = label
+			}
+		}
+
+		colour := 0
+		for neighborColours[next][colour] {
+			colour++
+		}
+		colours[next] = colour
+		for neighbor := range a.shared[next] {
+			neighborColours[neighbor][colour] = true
+		}
+	}
+	return colours
+}
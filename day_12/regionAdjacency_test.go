@@ -0,0 +1,88 @@
+package main
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestAdjacency(t *testing.T) {
+	labels := LabelRegions(parseInput(`AAAA
+BBCD
+BBCC
+EEEC`))
+	adjacency := labels.Adjacency()
+
+	// A 0, B 1, C 2, D 3, E 4
+	wantNeighbors := [][]int{{1, 2, 3}, {0, 2, 4}, {0, 1, 3, 4}, {0, 2}, {1, 2}}
+	for label, want := range wantNeighbors {
+		if got := adjacency.Neighbors(label); !slices.Equal(got, want) {
+			t.Errorf("%d: expected neighbors %v, got %v", label, want, got)
+		}
+	}
+
+	tests := []struct {
+		label, other, want int
+	}{
+		{0, 1, 2}, {1, 0, 2}, {0, 2, 1}, {1, 2, 2}, {2, 4, 2}, {3, 4, 0},
+	}
+	for _, tt := range tests {
+		if got := adjacency.SharedFences(tt.label, tt.other); got != tt.want {
+			t.Errorf("%d-%d: expected %d shared fences, got %d", tt.label, tt.other, tt.want, got)
+		}
+	}
+}
+
+func TestEnclosures(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[int]int
+	}{
+		{"small", "AAAA\nBBCD\nBBCC\nEEEC", map[int]int{}},
+		{"four holes", "OOOOO\nOXOXO\nOOOOO\nOXOXO\nOOOOO", map[int]int{1: 0, 2: 0, 3: 0, 4: 0}},
+		{"nested", "AAAAA\nABBBA\nABCBA\nABBBA\nAAAAA", map[int]int{1: 0, 2: 1}},
+		// B and C are inside A, but neither is surrounded by A alone
+		{"two in a hole", "AAAA\nABCA\nAAAA", map[int]int{}},
+		{"larger", largerExample, map[int]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LabelRegions(parseInput(tt.input)).Adjacency().Enclosures(); !maps.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestColouring(t *testing.T) {
+	rng := rand.New(rand.NewSource(50))
+	for _, gardenMap := range [][][]rune{parseInput(largerExample), randomGardenMap(rng, 140, 140, "ABCDE")} {
+		adjacency := LabelRegions(gardenMap).Adjacency()
+		colours := adjacency.Colouring()
+		for label, colour := range colours {
+			for _, neighbor := range adjacency.Neighbors(label) {
+				if colours[neighbor] == colour {
+					t.Fatalf("regions %d and %d touch and have the same colour %d", label, neighbor, colour)
+				}
+			}
+		}
+		// regions on a map can always be coloured with 4 colours, the heuristic should not be far off
+		if n := slices.Max(colours) + 1; n > 5 {
+			t.Errorf("expected at most 5 colours, got %d", n)
+		}
+	}
+}
+
+func TestEmptyGarden(t *testing.T) {
+	labels := LabelRegions(parseInput(""))
+	if len(labels.Regions()) != 0 {
+		t.Fatalf("expected no regions, got %v", labels.Regions())
+	}
+	adjacency := labels.Adjacency()
+	if len(adjacency.Colouring()) != 0 || len(adjacency.Enclosures()) != 0 {
+		t.Errorf("expected no colours and no enclosures, got %v and %v", adjacency.Colouring(), adjacency.Enclosures())
+	}
+}
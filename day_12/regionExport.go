@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// the fill of each colour of the map colouring, greedy colouring can go beyond the palette on large maps
+var svgPalette = []string{"#8dd3c7", "#ffffb3", "#bebada", "#fb8072", "#80b1d3", "#fdb462"}
+
+func svgFill(colour int) string {
+	if colour < len(svgPalette) {
+		return svgPalette[colour]
+	}
+	return fmt.Sprintf("hsl(%d, 60%%, 70%%)", colour*47%360)
+}
+
+// the path of the rings, scaled to cellSize pixels per plot
+func svgPath(rings []Ring, cellSize int) string {
+	var sb strings.Builder
+	for _, ring := range rings {
+		for i, point := range ring {
+			command := "L"
+			if i == 0 {
+				command = "M"
+			}
+			fmt.Fprintf(&sb, "%s%d %d ", command, point.x*cellSize, point.y*cellSize)
+		}
+		sb.WriteString("Z ")
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// write the regions as SVG, cellSize pixels per plot. Each region is a path, its holes cut out, with its ID, plant,
+// area and perimeter as data attributes and as its title. Touching regions have different colours
+func (r *RegionLabels) WriteSVG(w io.Writer, cellSize int) error {
+	colours := r.Adjacency().Colouring()
+
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		r.cols*cellSize, r.rows*cellSize, r.cols*cellSize, r.rows*cellSize)
+	for _, polygon := range r.Polygons() {
+		region := polygon.region
+		plant := html.EscapeString(string(region.plant))
+		fill := svgFill(colours[region.label])
+		fmt.Fprintf(writer, `  <path d="%s" fill="%s" fill-rule="evenodd" stroke="black" data-id="%s" data-plant="%s" data-area="%d" data-perimeter="%d">`,
+			svgPath(append([]Ring{polygon.outer}, polygon.holes...), cellSize), fill,
+			html.EscapeString(region.ID()), plant, region.area, region.perimeter)
+		fmt.Fprintf(writer, "<title>%s: %s, area %d, perimeter %d</title></path>\n",
+			html.EscapeString(region.ID()), plant, region.area, region.perimeter)
+	}
+	fmt.Fprintln(writer, "</svg>")
+	return writer.Flush()
+}
+
+type geoJSONGeometry struct {
+	Type        string     `json:"type"`
+	Coordinates [][][2]int `json:"coordinates"`
+}
+
+type geoJSONProperties struct {
+	ID        string `json:"id"`
+	Plant     string `json:"plant"`
+	Area      int    `json:"area"`
+	Perimeter int    `json:"perimeter"`
+}
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   geoJSONGeometry   `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// the ring as GeoJSON positions, with y going up from the bottom of the garden, and the first position repeated at
+// the end. Flipping y keeps the way the ring turns as seen on the map, so the points are reversed: the clockwise
+// outer rings become anticlockwise and the holes clockwise, as RFC 7946 wants them
+func (r *RegionLabels) geoJSONRing(ring Ring) [][2]int {
+	positions := make([][2]int, 0, len(ring)+1)
+	for i := len(ring) - 1; i >= 0; i-- {
+		positions = append(positions, [2]int{ring[i].x, r.rows - ring[i].y})
+	}
+	return append(positions, positions[0])
+}
+
+// write the regions as a GeoJSON feature collection, one polygon feature per region, in plot units, with its ID,
+// plant, area and perimeter as properties
+func (r *RegionLabels) WriteGeoJSON(w io.Writer) error {
+	collection := geoJSONFeatureCollection{"FeatureCollection", make([]geoJSONFeature, 0, len(r.regions))}
+	for _, polygon := range r.Polygons() {
+		coordinates := [][][2]int{r.geoJSONRing(polygon.outer)}
+		for _, hole := range polygon.holes {
+			coordinates = append(coordinates, r.geoJSONRing(hole))
+		}
+
+		region := polygon.region
+		collection.Features = append(collection.Features, geoJSONFeature{
+			"Feature",
+			geoJSONGeometry{"Polygon", coordinates},
+			geoJSONProperties{region.ID(), string(region.plant), region.area, region.perimeter},
+		})
+	}
+	return json.NewEncoder(w).Encode(collection)
+}
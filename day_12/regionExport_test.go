@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteGeoJSON(t *testing.T) {
+	labels := LabelRegions(parseInput("AAA\nABA\nAAA"))
+	var buf bytes.Buffer
+	if err := labels.WriteGeoJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var collection geoJSONFeatureCollection
+	if err := json.Unmarshal(buf.Bytes(), &collection); err != nil {
+		t.Fatal(err)
+	}
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 2 {
+		t.Fatalf("expected a feature collection of 2 features, got %v", collection)
+	}
+
+	ring := collection.Features[0]
+	if want := (geoJSONProperties{"A_0_0", "A", 8, 16}); ring.Properties != want {
+		t.Errorf("expected properties %v, got %v", want, ring.Properties)
+	}
+	if ring.Geometry.Type != "Polygon" || len(ring.Geometry.Coordinates) != 2 {
+		t.Fatalf("expected a polygon with an outer ring and a hole, got %v", ring.Geometry)
+	}
+
+	// y goes up, the outer rings are anticlockwise and the holes clockwise, all closed
+	t.Run("winding", func(t *testing.T) {
+		labels := LabelRegions(parseInput("OOOOO\nOXOXO\nOOOOO\nOXOXO\nOOOOO"))
+		var buf bytes.Buffer
+		if err := labels.WriteGeoJSON(&buf); err != nil {
+			t.Fatal(err)
+		}
+		var collection geoJSONFeatureCollection
+		if err := json.Unmarshal(buf.Bytes(), &collection); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, feature := range collection.Features {
+			for i, positions := range feature.Geometry.Coordinates {
+				if len(positions) < 4 || positions[0] != positions[len(positions)-1] {
+					t.Fatalf("%v: expected a closed ring, got %v", feature.Properties.ID, positions)
+				}
+				ring := make(Ring, 0, len(positions)-1)
+				for _, position := range positions[:len(positions)-1] {
+					ring = append(ring, Point{position[0], position[1]})
+				}
+				// with y going up, signedArea2 is positive for an anticlockwise ring
+				if anticlockwise := ring.signedArea2() > 0; anticlockwise != (i == 0) {
+					t.Errorf("%v: ring %d %v, expected anticlockwise %v", feature.Properties.ID, i, positions, i == 0)
+				}
+			}
+		}
+	})
+}
+
+func TestWriteSVG(t *testing.T) {
+	labels := LabelRegions(parseInput("AAA\nABA\nAAA"))
+	var buf bytes.Buffer
+	if err := labels.WriteSVG(&buf, 10); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="30" height="30" viewBox="0 0 30 30">`,
+		`d="M0 0 L30 0 L30 30 L0 30 Z M10 10 L10 20 L20 20 L20 10 Z"`,
+		`data-id="A_0_0" data-plant="A" data-area="8" data-perimeter="16"`,
+		`data-id="B_1_1" data-plant="B" data-area="1" data-perimeter="4"`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("expected the SVG to contain %s, got\n%s", want, svg)
+		}
+	}
+	if paths := strings.Count(svg, "<path "); paths != 2 {
+		t.Errorf("expected 2 paths, got %d", paths)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"slices"
+)
+
+// the 8 neighbors of a plot, plots of a hole can touch by a corner
+var allDirections = [][2]int{
+	{0, 1}, {1, 0}, {0, -1}, {-1, 0},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// whether the plot has a fence on the side of dir: the plot on that side is in another region, or outside the garden
+func (r *RegionLabels) fenced(x, y int, dir [2]int) bool {
+	return r.Label(x+dir[0], y+dir[1]) != r.Label(x, y)
+}
+
+// the geometry of every region:
+//   - area and perimeter, by counting plots and fences
+//   - sides, by counting corners: a region has as many sides as corners, around the outside and around the holes.
+//     Corners are found in the 2x2 windows of plots, including the windows across the edge of the garden: a window
+//     with 1 or 3 plots of the region has a corner, a window with 2 plots on a diagonal has two
+//   - holes, from the Euler number of the region, one minus the number of its holes, which adds up over the same windows:
+//     (windows with 1 plot - windows with 3 plots + 2 * diagonal windows) / 4
+func (r *RegionLabels) measure() {
+	for x := 0; x < r.rows; x++ {
+		for y := 0; y < r.cols; y++ {
+			region := &r.regions[r.Label(x, y)]
+			region.area++
+			for _, dir := range directions {
+				if r.fenced(x, y, dir) {
+					region.perimeter++
+				}
+			}
+		}
+	}
+
+	euler := make([]int, len(r.regions)) // four times the Euler number
+	for x := -1; x < r.rows; x++ {
+		for y := -1; y < r.cols; y++ {
+			// clockwise, so opposite plots are on a diagonal
+			window := [4]int{r.Label(x, y), r.Label(x, y+1), r.Label(x+1, y+1), r.Label(x+1, y)}
+			for i, label := range window {
+				// each region in the window once
+				if label == -1 || slices.Index(window[:], label) < i {
+					continue
+				}
+
+				plots := 0
+				for _, other := range window {
+					if other == label {
+						plots++
+					}
+				}
+				switch {
+				case plots == 1:
+					r.regions[label].sides++
+					euler[label]++
+				case plots == 3:
+					r.regions[label].sides++
+					euler[label]--
+				case plots == 2 && window[(i+2)%4] == label:
+					r.regions[label].sides += 2
+					euler[label] += 2
+				}
+			}
+		}
+	}
+
+	for label := range r.regions {
+		r.regions[label].holes = 1 - euler[label]/4
+	}
+}
+
+// the plots of each hole of the region. A hole is a group of plots outside the region, touching by a side or a corner,
+// which can't reach the edge of the garden without crossing the region
+func (r *RegionLabels) Holes(label int) [][]Location {
+	holes := make([][]Location, 0)
+	if r.regions[label].holes == 0 {
+		return holes
+	}
+
+	// from the edge of the garden, reach all the plots outside the region and not in a hole
+	reached := make([]bool, len(r.labels))
+	stack := make([]int, 0)
+	reach := func(x, y int) {
+		if index := x*r.cols + y; r.inside(x, y) && !reached[index] && r.labels[index] != label {
+			reached[index] = true
+			stack = append(stack, index)
+		}
+	}
+
+	// reach the plots outside the region from the plots on the stack, add them to the hole if there is one
+	fill := func(hole *[]Location) {
+		for len(stack) > 0 {
+			index := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if hole != nil {
+				*hole = append(*hole, Location{index / r.cols, index % r.cols})
+			}
+			for _, dir := range allDirections {
+				reach(index/r.cols+dir[0], index%r.cols+dir[1])
+			}
+		}
+	}
+	for x := 0; x < r.rows; x++ {
+		reach(x, 0)
+		reach(x, r.cols-1)
+	}
+	for y := 0; y < r.cols; y++ {
+		reach(0, y)
+		reach(r.rows-1, y)
+	}
+	fill(nil)
+
+	// the plots left are in holes, fill each hole in turn
+	for index := range r.labels {
+		if reached[index] || r.labels[index] == label {
+			continue
+		}
+		hole := make([]Location, 0)
+		reach(index/r.cols, index%r.cols)
+		fill(&hole)
+		holes = append(holes, hole)
+	}
+	return holes
+}
+
+// the labels of the regions inside the holes of the region, including the regions inside those, in label order
+func (r *RegionLabels) Enclosed(label int) []int {
+	enclosed := make([]int, 0)
+	seen := make([]bool, len(r.regions))
+	for _, hole := range r.Holes(label) {
+		for _, plot := range hole {
+			if other := r.Label(plot.x, plot.y); !seen[other] {
+				seen[other] = true
+				enclosed = append(enclosed, other)
+			}
+		}
+	}
+	slices.Sort(enclosed)
+	return enclosed
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRegionGeometry(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		plot     Location // a plot of the region to measure
+		want     Region
+		enclosed []rune // the plants of the enclosed regions, in label order
+	}{
+		{"single plot", "A", Location{0, 0}, Region{0, 'A', Location{0, 0}, 1, 4, 4, 0}, nil},
+		{"ring on the edges", "AAA\nABA\nAAA", Location{0, 0}, Region{0, 'A', Location{0, 0}, 8, 16, 8, 1}, []rune{'B'}},
+		{"hole of a ring", "AAA\nABA\nAAA", Location{1, 1}, Region{1, 'B', Location{1, 1}, 1, 4, 4, 0}, nil},
+		{"open to the edge", "ABA\nABA\nAAA", Location{2, 0}, Region{0, 'A', Location{0, 0}, 7, 16, 8, 0}, nil},
+		{"four holes", "OOOOO\nOXOXO\nOOOOO\nOXOXO\nOOOOO", Location{0, 0}, Region{0, 'O', Location{0, 0}, 21, 36, 20, 4}, []rune{'X', 'X', 'X', 'X'}},
+		{"nested", "AAAAA\nABBBA\nABCBA\nABBBA\nAAAAA", Location{0, 0}, Region{0, 'A', Location{0, 0}, 16, 32, 8, 1}, []rune{'B', 'C'}},
+		{"inside nested", "AAAAA\nABBBA\nABCBA\nABBBA\nAAAAA", Location{1, 1}, Region{1, 'B', Location{1, 1}, 8, 16, 8, 1}, []rune{'C'}},
+		// the two B plots touch by a corner, they make one hole
+		{"diagonal hole", "AAAA\nABAA\nAABA\nAAAA", Location{0, 0}, Region{0, 'A', Location{0, 0}, 14, 24, 12, 1}, []rune{'B', 'B'}},
+		// plots touching by a corner aren't neighbors, each of the four plots is a region of its own
+		{"diagonal on the edge", "AB\nBA", Location{0, 1}, Region{1, 'B', Location{0, 1}, 1, 4, 4, 0}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gardenMap := parseInput(tt.input)
+			labels := LabelRegions(gardenMap)
+			label := labels.Label(tt.plot.x, tt.plot.y)
+			if got := labels.Regions()[label]; got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+
+			plants := make([]rune, 0)
+			for _, other := range labels.Enclosed(label) {
+				plants = append(plants, labels.Regions()[other].plant)
+			}
+			if !slices.Equal(plants, tt.enclosed) && len(plants)+len(tt.enclosed) > 0 {
+				t.Errorf("expected enclosed regions %c, got %c", tt.enclosed, plants)
+			}
+
+			if len(labels.Holes(label)) != tt.want.holes {
+				t.Errorf("expected %d holes, got %d", tt.want.holes, len(labels.Holes(label)))
+			}
+
+			// the perimeter counted plot by plot agrees
+			locations := labels.Locations()[label]
+			_, perimeter := regionCostByContains(gardenMap, &LocationGroup{"", locations, Region{}})
+			if perimeter != tt.want.perimeter {
+				t.Errorf("expected the perimeter counted plot by plot %d, got %d", tt.want.perimeter, perimeter)
+			}
+		})
+	}
+
+	t.Run("diagonal regions", func(t *testing.T) {
+		if got := len(LabelRegions(parseInput("AB\nBA")).Regions()); got != 4 {
+			t.Errorf("expected 4 regions, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+// a region of plots with the same plant
+type Region struct {
+	label     int
+	plant     rune
+	start     Location // the first plot of the region, row by row
+	area      int
+	perimeter int
+	sides     int
+	holes     int // areas of other plots the region surrounds completely
+}
+
+// the regions of the garden, with the label of the region of each plot
+type RegionLabels struct {
+	rows, cols int
+	labels     []int // by plot index row*cols+col
+	regions    []Region
+}
+
+// label the regions with a flood fill over the plot indexes, in the order of their first plot row by row, then measure
+// them all in one more pass. Both passes look at each plot and its neighbors once
+func LabelRegions(gardenMap [][]rune) *RegionLabels {
+	rows := len(gardenMap)
+	cols := 0
+	if rows > 0 {
+		cols = len(gardenMap[0])
+	}
+	r := &RegionLabels{rows, cols, make([]int, rows*cols), make([]Region, 0)}
+	for i := range r.labels {
+		r.labels[i] = -1
+	}
+
+	stack := make([]int, 0)
+	for start := range r.labels {
+		if r.labels[start] != -1 {
+			continue
+		}
+
+		label := len(r.regions)
+		plant := gardenMap[start/cols][start%cols]
+		r.regions = append(r.regions, Region{label: label, plant: plant, start: Location{start / cols, start % cols}})
+		r.labels[start] = label
+		stack = append(stack, start)
+		for len(stack) > 0 {
+			index := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, dir := range directions {
+				x, y := index/cols+dir[0], index%cols+dir[1]
+				if r.inside(x, y) && r.labels[x*cols+y] == -1 && gardenMap[x][y] == plant {
+					r.labels[x*cols+y] = label
+					stack = append(stack, x*cols+y)
+				}
+			}
+		}
+	}
+
+	r.measure()
+	return r
+}
+
+func (r *RegionLabels) inside(x, y int) bool {
+	return x >= 0 && x < r.rows && y >= 0 && y < r.cols
+}
+
+// the label of the region of the plot, -1 outside the garden
+func (r *RegionLabels) Label(x, y int) int {
+	if !r.inside(x, y) {
+		return -1
+	}
+	return r.labels[x*r.cols+y]
+}
+
+// the regions, by label
+func (r *RegionLabels) Regions() []Region {
+	return r.regions
+}
+
+// the plots of each region, by label
+func (r *RegionLabels) Locations() [][]Location {
+	locations := make([][]Location, len(r.regions))
+	for index, label := range r.labels {
+		locations[label] = append(locations[label], Location{index / r.cols, index % r.cols})
+	}
+	return locations
+}
+
+// the total price of fencing with the perimeter
+func (r *RegionLabels) Price() int {
+	price := 0
+	for _, region := range r.regions {
+		price += region.area * region.perimeter
+	}
+	return price
+}
+
+// the total price of fencing with the number of sides
+func (r *RegionLabels) DiscountPrice() int {
+	price := 0
+	for _, region := range r.regions {
+		price += region.area * region.sides
+	}
+	return price
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const largerExample = `RRRRIICCFF
+RRRRIICCCF
+VVRRRCCFFF
+VVRCCCJFFF
+VVVVCJJCFE
+VVIVCCJJEE
+VVIIICJJEE
+MIIIIIJJEE
+MIIISIJEEE
+MMMISSJEEE`
+
+func TestLabelRegions(t *testing.T) {
+	gardenMap := parseInput(`AAAA
+BBCD
+BBCC
+EEEC`)
+	labels := LabelRegions(gardenMap)
+
+	want := []Region{
+		{0, 'A', Location{0, 0}, 4, 10, 4, 0},
+		{1, 'B', Location{1, 0}, 4, 8, 4, 0},
+		{2, 'C', Location{1, 2}, 4, 10, 8, 0},
+		{3, 'D', Location{1, 3}, 1, 4, 4, 0},
+		{4, 'E', Location{3, 0}, 3, 8, 4, 0},
+	}
+	got := labels.Regions()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d regions, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want[i], got[i])
+		}
+	}
+
+	if labels.Label(2, 3) != 2 || labels.Label(-1, 0) != -1 {
+		t.Errorf("expected labels 2 and -1, got %d and %d", labels.Label(2, 3), labels.Label(-1, 0))
+	}
+}
+
+func TestPrices(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		price         int
+		discountPrice int
+	}{
+		{"small", "AAAA\nBBCD\nBBCC\nEEEC", 140, 80},
+		{"enclosed", "OOOOO\nOXOXO\nOOOOO\nOXOXO\nOOOOO", 772, 436},
+		{"E shaped", "EEEEE\nEXXXX\nEEEEE\nEXXXX\nEEEEE", 692, 236},
+		{"larger", largerExample, 1930, 1206},
+		{"trailing newline", largerExample + "\n", 1930, 1206},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels := LabelRegions(parseInput(tt.input))
+			if got := labels.Price(); got != tt.price {
+				t.Errorf("expected price %d, got %d", tt.price, got)
+			}
+			if got := labels.DiscountPrice(); got != tt.discountPrice {
+				t.Errorf("expected discount price %d, got %d", tt.discountPrice, got)
+			}
+		})
+	}
+}
+
+func randomGardenMap(rng *rand.Rand, rows, cols int, plants string) [][]rune {
+	lines := make([]string, rows)
+	for i := range lines {
+		line := make([]byte, cols)
+		for j := range line {
+			line[j] = plants[rng.Intn(len(plants))]
+		}
+		lines[i] = string(line)
+	}
+	return parseInput(strings.Join(lines, "\n"))
+}
+
+// the original measures of a group, looking up every plot in the list of the group's plots, kept as the reference
+// for the measures of LabelRegions
+func regionCostByContains(gardenMap [][]rune, region *LocationGroup) (area int, perimeter int) {
+	area = len(region.locations)
+
+	perimeter = 4 * area
+	for _, location := range region.locations {
+
+		for _, dir := range directions {
+			neighbor := Location{location.x + dir[0], location.y + dir[1]}
+			if neighbor.x >= 0 && neighbor.x < len(gardenMap) && neighbor.y >= 0 && neighbor.y < len(gardenMap[0]) {
+				if slices.Contains(region.locations, neighbor) {
+					perimeter = perimeter - 1
+				}
+			}
+		}
+
+	}
+
+	return
+}
+
+func side_count_update(current_type bool) int {
+	if current_type {
+		return 0
+	}
+	return 1
+}
+
+func horizontalSidesBySweep(gardenMap [][]rune, region *LocationGroup) int {
+
+	// a horizontal side is created when the plot is in the region and the plot above it is not in the region, call it type_1_side, (or vice versa call it type_2_side)
+	// side ends when the plot and the one above it is in the same region
+
+	// plots outside the garden boundary are treated as not	in the region
+
+	total_sides := 0
+
+	for row := 0; row <= len(gardenMap); row++ {
+		type_1_side := 0
+		type_2_side := 0
+		type_1_side_ongoing := false
+		type_2_side_ongoing := false
+
+		for col := 0; col < len(gardenMap[0]); col++ {
+			currentPlot := Location{row, col}
+			upperPlot := Location{row - 1, col}
+
+			// check for type_1_side
+			if slices.Contains(region.locations, currentPlot) && !slices.Contains(region.locations, upperPlot) {
+				// we may or may not update the type_1_side count
+				type_1_side += side_count_update(type_1_side_ongoing)
+				type_1_side_ongoing = true
+				type_2_side_ongoing = false
+				continue
+			}
+
+			// check for type_2_side
+			if !slices.Contains(region.locations, currentPlot) && slices.Contains(region.locations, upperPlot) {
+				// we may or may not update the type_2_side count
+				type_2_side += side_count_update(type_2_side_ongoing)
+				type_2_side_ongoing = true
+				type_1_side_ongoing = false
+				continue
+			}
+
+			// if both plots are in the region or outside the region, we stop the ongoing side
+			type_1_side_ongoing = false
+			type_2_side_ongoing = false
+		}
+
+		// fmt.Println("row", row, "type_1_side", type_1_side, "type_2_side", type_2_side)
+
+		total_sides += type_1_side + type_2_side
+	}
+
+	return total_sides
+
+}
+
+func verticalSidesBySweep(gardenMap [][]rune, region *LocationGroup) int {
+
+	// a vertical side is created when the plot is in the region and the plot to the left of it is not in the region, call it type_1_side, (or vice versa call it type_2_side)
+	// side ends when the plot and the one to the left of it is in the same region
+
+	// plots outside the garden boundary are treated as not	in the region
+
+	total_sides := 0
+
+	for col := 0; col <= len(gardenMap[0]); col++ {
+		type_1_side := 0
+		type_2_side := 0
+		type_1_side_ongoing := false
+		type_2_side_ongoing := false
+
+		for row := 0; row < len(gardenMap); row++ {
+			currentPlot := Location{row, col}
+			leftPlot := Location{row, col - 1}
+
+			// check for type_1_side
+			if slices.Contains(region.locations, currentPlot) && !slices.Contains(region.locations, leftPlot) {
+				type_1_side += side_count_update(type_1_side_ongoing)
+				type_1_side_ongoing = true
+				type_2_side_ongoing = false
+				continue
+			}
+
+			// check for type_2_side
+			if !slices.Contains(region.locations, currentPlot) && slices.Contains(region.locations, leftPlot) {
+				type_2_side += side_count_update(type_2_side_ongoing)
+				type_2_side_ongoing = true
+				type_1_side_ongoing = false
+				continue
+			}
+
+			type_1_side_ongoing = false
+			type_2_side_ongoing = false
+		}
+
+		total_sides += type_1_side + type_2_side
+	}
+
+	return total_sides
+}
+
+func TestLabelRegionsMatchesSweeps(t *testing.T) {
+	rng := rand.New(rand.NewSource(12))
+	for range 200 {
+		gardenMap := randomGardenMap(rng, 1+rng.Intn(10), 1+rng.Intn(10), "AABC")
+		labels := LabelRegions(gardenMap)
+		for _, group := range FindGroups(gardenMap) {
+			region := labels.Regions()[labels.Label(group.locations[0].x, group.locations[0].y)]
+			if region.ID() != group.groupID {
+				t.Fatalf("expected the region of %v, got %v", group.groupID, region.ID())
+			}
+			area, perimeter := regionCostByContains(gardenMap, group)
+			if region.area != area || region.perimeter != perimeter {
+				t.Fatalf("%v: expected area %d and perimeter %d, got %d and %d", group.groupID, area, perimeter, region.area, region.perimeter)
+			}
+			horizontal, vertical := horizontalSidesBySweep(gardenMap, group), verticalSidesBySweep(gardenMap, group)
+			if region.sides != horizontal+vertical {
+				t.Fatalf("%v in %v: expected %d horizontal and %d vertical sides, got %d sides", group.groupID, gardenMap, horizontal, vertical, region.sides)
+			}
+
+			// the Calculate functions measure the plots of the group, not the region FindGroups stored
+			plots := &LocationGroup{"", group.locations, Region{}}
+			if gotArea, gotPerimeter := CalculateRegionCost(gardenMap, plots); gotArea != area || gotPerimeter != perimeter {
+				t.Fatalf("%v: expected area %d and perimeter %d, got %d and %d", group.groupID, area, perimeter, gotArea, gotPerimeter)
+			}
+			if got, want := CalculateHorizontalSides(gardenMap, plots), horizontal; got != want {
+				t.Fatalf("%v in %v: expected %d horizontal sides, got %d", group.groupID, gardenMap, want, got)
+			}
+			if got, want := CalculateVerticalSides(gardenMap, plots), vertical; got != want {
+				t.Fatalf("%v in %v: expected %d vertical sides, got %d", group.groupID, gardenMap, want, got)
+			}
+			if holes := len(labels.Holes(region.label)); region.holes != holes {
+				t.Fatalf("%v in %v: expected %d holes, got %d", group.groupID, gardenMap, holes, region.holes)
+			}
+		}
+	}
+}
+
+func BenchmarkLabelRegions(b *testing.B) {
+	gardenMap := randomGardenMap(rand.New(rand.NewSource(12)), 140, 140, "ABCD")
+	b.ResetTimer()
+	for range b.N {
+		LabelRegions(gardenMap)
+	}
+}
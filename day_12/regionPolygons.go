@@ -0,0 +1,158 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+)
+
+// a corner of plots: x is the column and y the row, from the top left corner of the garden
+type Point struct {
+	x, y int
+}
+
+// a closed ring of points, the last point connects back to the first. Only the points where the ring turns are kept
+type Ring []Point
+
+// twice the signed area of the ring, with y going down: positive when the ring goes clockwise on the map
+func (ring Ring) signedArea2() int {
+	area := 0
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		area += p.x*q.y - q.x*p.y
+	}
+	return area
+}
+
+// the boundary of a region: one outer ring, clockwise, and a ring around each hole, anticlockwise.
+// Where the region touches itself by a corner, the rings are kept apart and touch at that corner, as GIS tools
+// expect of a valid polygon. A hole of plots touching by a corner is then traced as several rings
+type RegionPolygon struct {
+	region Region
+	outer  Ring
+	holes  []Ring
+}
+
+// the same ID as the group of the region's plots in FindGroups
+func (region Region) ID() string {
+	return formatGroupID(region.plant, region.start)
+}
+
+// a unit fence, going around its plot clockwise, so the plot is on its right
+type fenceEdge struct {
+	from Point
+	dir  [2]int // dx, dy
+}
+
+// the next direction after turning left, with y going down
+func turnLeft(dir [2]int) [2]int {
+	return [2]int{dir[1], -dir[0]}
+}
+
+// the fences of the plot, clockwise from the top one
+func (r *RegionLabels) plotFences(x, y int) []fenceEdge {
+	fences := make([]fenceEdge, 0, 4)
+	label := r.Label(x, y)
+	// plot x is the row and y the column, points are the other way around
+	if r.Label(x-1, y) != label {
+		fences = append(fences, fenceEdge{Point{y, x}, [2]int{1, 0}})
+	}
+	if r.Label(x, y+1) != label {
+		fences = append(fences, fenceEdge{Point{y + 1, x}, [2]int{0, 1}})
+	}
+	if r.Label(x+1, y) != label {
+		fences = append(fences, fenceEdge{Point{y + 1, x + 1}, [2]int{-1, 0}})
+	}
+	if r.Label(x, y-1) != label {
+		fences = append(fences, fenceEdge{Point{y, x + 1}, [2]int{0, -1}})
+	}
+	return fences
+}
+
+// trace the polygons of all the regions, by label, in one pass over the plots then one walk along each fence
+func (r *RegionLabels) Polygons() []RegionPolygon {
+	// the fences of each region, by the point they start from. A point has two fences of the region starting from it
+	// where the region touches itself by a corner
+	fences := make([]map[Point][]fenceEdge, len(r.regions))
+	for label := range fences {
+		fences[label] = make(map[Point][]fenceEdge)
+	}
+	for x := 0; x < r.rows; x++ {
+		for y := 0; y < r.cols; y++ {
+			regionFences := fences[r.Label(x, y)]
+			for _, fence := range r.plotFences(x, y) {
+				regionFences[fence.from] = append(regionFences[fence.from], fence)
+			}
+		}
+	}
+
+	polygons := make([]RegionPolygon, len(r.regions))
+	for label, region := range r.regions {
+		polygons[label] = RegionPolygon{region: region, holes: make([]Ring, 0)}
+		// the top fence of the first plot is on the outer ring
+		start := Point{region.start.y, region.start.x}
+		polygons[label].outer = traceRing(fences[label], fences[label][start][0])
+		for len(fences[label]) > 0 {
+			for _, next := range fences[label] {
+				polygons[label].holes = append(polygons[label].holes, traceRing(fences[label], next[0]))
+				break
+			}
+		}
+		slices.SortFunc(polygons[label].holes, func(a, b Ring) int { return comparePoints(a[0], b[0]) })
+	}
+	return polygons
+}
+
+// take the fence out of the fences
+func removeFence(fences map[Point][]fenceEdge, fence fenceEdge) {
+	next := fences[fence.from]
+	i := slices.Index(next, fence)
+	if len(next) == 1 {
+		delete(fences, fence.from)
+	} else {
+		fences[fence.from] = slices.Delete(next, i, i+1)
+	}
+}
+
+// follow the fences from the start fence until back to it, removing them as they are walked. Where two fences go on
+// from a point, the region touches itself by a corner there: take the fence turning left, to go around the plot
+// outside the region and keep the rings apart. The ring starts from its top left point
+func traceRing(fences map[Point][]fenceEdge, start fenceEdge) Ring {
+	ring := make(Ring, 0)
+	fence := start
+	dir := [2]int{0, 0}
+	for {
+		removeFence(fences, fence)
+		if fence.dir != dir {
+			ring = append(ring, fence.from)
+			dir = fence.dir
+		}
+
+		point := Point{fence.from.x + dir[0], fence.from.y + dir[1]}
+		next := fences[point]
+		if point == start.from && (len(next) == 0 || turnLeft(dir) == start.dir) {
+			break
+		}
+		fence = next[0]
+		if len(next) > 1 && next[1].dir == turnLeft(dir) {
+			fence = next[1]
+		}
+	}
+
+	// the start point is a corner only if the ring turns there
+	if dir == start.dir {
+		ring = ring[1:]
+	}
+
+	first := 0
+	for i, point := range ring {
+		if comparePoints(point, ring[first]) < 0 {
+			first = i
+		}
+	}
+	return slices.Concat(ring[first:], ring[:first])
+}
+
+// top to bottom, then left to right
+func comparePoints(a, b Point) int {
+	return cmp.Or(cmp.Compare(a.y, b.y), cmp.Compare(a.x, b.x))
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestPolygons(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		plot  Location
+		outer Ring
+		holes []Ring
+	}{
+		{"single plot", "A", Location{0, 0}, Ring{{0, 0}, {1, 0}, {1, 1}, {0, 1}}, []Ring{}},
+		{"ring", "AAA\nABA\nAAA", Location{0, 0},
+			Ring{{0, 0}, {3, 0}, {3, 3}, {0, 3}},
+			[]Ring{{{1, 1}, {1, 2}, {2, 2}, {2, 1}}}},
+		{"L shape", "AB\nAA", Location{0, 0}, Ring{{0, 0}, {1, 0}, {1, 1}, {2, 1}, {2, 2}, {0, 2}}, []Ring{}},
+		// the hole is two plots touching by a corner, traced as two rings touching at that corner
+		{"diagonal hole", "AAAA\nABAA\nAABA\nAAAA", Location{0, 0},
+			Ring{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+			[]Ring{{{1, 1}, {1, 2}, {2, 2}, {2, 1}}, {{2, 2}, {2, 3}, {3, 3}, {3, 2}}}},
+		// the region touches itself by a corner around the B plot, which is not a hole: the B plot is cut out
+		// with a ring touching the outer ring
+		{"touching itself", "AAB\nABA\nAAA", Location{0, 0},
+			Ring{{0, 0}, {2, 0}, {2, 1}, {3, 1}, {3, 3}, {0, 3}},
+			[]Ring{{{1, 1}, {1, 2}, {2, 2}, {2, 1}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels := LabelRegions(parseInput(tt.input))
+			polygon := labels.Polygons()[labels.Label(tt.plot.x, tt.plot.y)]
+			if !slices.Equal(polygon.outer, tt.outer) {
+				t.Errorf("expected outer ring %v, got %v", tt.outer, polygon.outer)
+			}
+			if !slices.EqualFunc(polygon.holes, tt.holes, slices.Equal) {
+				t.Errorf("expected holes %v, got %v", tt.holes, polygon.holes)
+			}
+		})
+	}
+}
+
+// the length of the ring, its sides are all horizontal or vertical
+func (ring Ring) length() int {
+	length := 0
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		length += max(p.x-q.x, q.x-p.x) + max(p.y-q.y, q.y-p.y)
+	}
+	return length
+}
+
+func TestPolygonsMatchRegions(t *testing.T) {
+	rng := rand.New(rand.NewSource(49))
+	for range 200 {
+		gardenMap := randomGardenMap(rng, 1+rng.Intn(10), 1+rng.Intn(10), "AABC")
+		for _, polygon := range LabelRegions(gardenMap).Polygons() {
+			region := polygon.region
+			area2, perimeter, corners := polygon.outer.signedArea2(), polygon.outer.length(), len(polygon.outer)
+			if area2 <= 0 {
+				t.Fatalf("%v in %v: the outer ring %v is not clockwise", region.ID(), gardenMap, polygon.outer)
+			}
+			for _, hole := range polygon.holes {
+				if hole.signedArea2() >= 0 {
+					t.Fatalf("%v in %v: the hole %v is not anticlockwise", region.ID(), gardenMap, hole)
+				}
+				area2 += hole.signedArea2()
+				perimeter += hole.length()
+				corners += len(hole)
+			}
+
+			if area2 != 2*region.area || perimeter != region.perimeter || corners != region.sides {
+				t.Fatalf("%v in %v: expected area %d, perimeter %d, sides %d, got %d, %d, %d",
+					region.ID(), gardenMap, region.area, region.perimeter, region.sides, area2/2, perimeter, corners)
+			}
+			if len(polygon.holes) < region.holes {
+				t.Fatalf("%v in %v: expected at least %d holes, got %v", region.ID(), gardenMap, region.holes, polygon.holes)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+// a node in the Aho-Corasick automaton
+type acNode struct {
+	children map[rune]int
+	fail     int   // the node of the longest proper suffix that is also a prefix of some word
+	outputs  []int // indices of the words ending at this node, including those reachable through fail links
+}
+
+// AhoCorasick is a multi-pattern matcher: it finds all occurrences of all its words in a single pass over the text
+type AhoCorasick struct {
+	nodes []acNode
+	words [][]rune
+}
+
+func NewAhoCorasick(words []string) *AhoCorasick {
+	ac := &AhoCorasick{nodes: []acNode{{children: map[rune]int{}}}}
+
+	// build the trie of all the words
+	for i, word := range words {
+		runes := []rune(word)
+		ac.words = append(ac.words, runes)
+		if len(runes) == 0 {
+			continue
+		}
+
+		current := 0
+		for _, r := range runes {
+			next, ok := ac.nodes[current].children[r]
+			if !ok {
+				next = len(ac.nodes)
+				ac.nodes = append(ac.nodes, acNode{children: map[rune]int{}})
+				ac.nodes[current].children[r] = next
+			}
+			current = next
+		}
+		ac.nodes[current].outputs = append(ac.nodes[current].outputs, i)
+	}
+
+	// build the fail links in BFS order, so the fail node of a node is always complete before the node itself
+	queue := make([]int, 0, len(ac.nodes))
+	for _, child := range ac.nodes[0].children {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for r, child := range ac.nodes[current].children {
+			fail := ac.nodes[current].fail
+			for fail != 0 {
+				if _, ok := ac.nodes[fail].children[r]; ok {
+					break
+				}
+				fail = ac.nodes[fail].fail
+			}
+			if next, ok := ac.nodes[fail].children[r]; ok {
+				ac.nodes[child].fail = next
+			}
+
+			ac.nodes[child].outputs = append(ac.nodes[child].outputs, ac.nodes[ac.nodes[child].fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+
+	return ac
+}
+
+// follow the automaton from state with the rune r, return the new state
+func (ac *AhoCorasick) next(state int, r rune) int {
+	for {
+		if next, ok := ac.nodes[state].children[r]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = ac.nodes[state].fail
+	}
+}
+
+// the length of the longest word in the automaton
+func (ac *AhoCorasick) MaxWordLength() int {
+	maxLength := 0
+	for _, word := range ac.words {
+		maxLength = max(maxLength, len(word))
+	}
+	return maxLength
+}
+
+// scan the text, call found with the word index and the index in text where the word starts, for every occurrence of every word
+func (ac *AhoCorasick) Scan(text []rune, found func(wordIndex, start int)) {
+	state := 0
+	for i, r := range text {
+		state = ac.next(state, r)
+		for _, wordIndex := range ac.nodes[state].outputs {
+			found(wordIndex, i-len(ac.words[wordIndex])+1)
+		}
+	}
+}
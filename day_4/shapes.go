@@ -0,0 +1,159 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// the mask character that matches any rune in the grid
+const Wildcard = '.'
+
+// the cross-MAS shape of part 2, as a text mask
+const CrossMasMask = `M.S
+.A.
+M.S`
+
+// a cell of a shape which must hold the given rune, offset is relative to the top-left corner of the shape
+type shapeCell struct {
+	offset Location
+	char   rune
+}
+
+// Shape is a small pattern of runes defined by a text mask, cells with the Wildcard in the mask match anything
+type Shape struct {
+	height, width int
+	cells         []shapeCell
+}
+
+// a shape found in the grid, the top-left corner of the variant's bounding box is at topLeft
+type ShapePlacement struct {
+	topLeft Location
+	variant int // index in Shape.Variants()
+}
+
+// parse a text mask into a shape, rows are separated by newlines and shorter rows are padded with wildcards
+func ParseShape(mask string) (Shape, error) {
+	lines := strings.Split(strings.Trim(mask, "\n"), "\n")
+
+	shape := Shape{height: len(lines)}
+	for i, line := range lines {
+		runes := []rune(strings.TrimRight(line, "\r"))
+		shape.width = max(shape.width, len(runes))
+		for j, r := range runes {
+			if r != Wildcard {
+				shape.cells = append(shape.cells, shapeCell{Location{i, j}, r})
+			}
+		}
+	}
+
+	if len(shape.cells) == 0 {
+		return Shape{}, errors.New("shape mask has no cells to match")
+	}
+	return shape.normalized(), nil
+}
+
+// sort the cells, so that equal shapes have equal cell lists
+func (s Shape) normalized() Shape {
+	cells := slices.Clone(s.cells)
+	slices.SortFunc(cells, func(a, b shapeCell) int {
+		return cmp.Or(cmp.Compare(a.offset.row, b.offset.row), cmp.Compare(a.offset.col, b.offset.col))
+	})
+	return Shape{s.height, s.width, cells}
+}
+
+// turn the shape 90 degrees clockwise
+func (s Shape) Rotate() Shape {
+	rotated := Shape{height: s.width, width: s.height}
+	for _, cell := range s.cells {
+		rotated.cells = append(rotated.cells, shapeCell{Location{cell.offset.col, s.height - 1 - cell.offset.row}, cell.char})
+	}
+	return rotated.normalized()
+}
+
+// mirror the shape left to right
+func (s Shape) Reflect() Shape {
+	reflected := Shape{height: s.height, width: s.width}
+	for _, cell := range s.cells {
+		reflected.cells = append(reflected.cells, shapeCell{Location{cell.offset.row, s.width - 1 - cell.offset.col}, cell.char})
+	}
+	return reflected.normalized()
+}
+
+func (s Shape) Equal(other Shape) bool {
+	return s.height == other.height && s.width == other.width && slices.Equal(s.cells, other.cells)
+}
+
+// the distinct rotations and reflections of the shape, the shape itself is always the first variant
+func (s Shape) Variants() []Shape {
+	variants := make([]Shape, 0, 8)
+	for _, start := range []Shape{s, s.Reflect()} {
+		current := start
+		for range 4 {
+			if !slices.ContainsFunc(variants, current.Equal) {
+				variants = append(variants, current)
+			}
+			current = current.Rotate()
+		}
+	}
+	return variants
+}
+
+// render the shape back to its text mask
+func (s Shape) String() string {
+	grid := make([][]rune, s.height)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(string(Wildcard), s.width))
+	}
+	for _, cell := range s.cells {
+		grid[cell.offset.row][cell.offset.col] = cell.char
+	}
+
+	lines := make([]string, s.height)
+	for i, row := range grid {
+		lines[i] = string(row)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// check if the shape matches the grid with its top-left corner at topLeft. The whole bounding box of the shape,
+// wildcards included, must be inside the grid
+func (s Shape) MatchesAt(matrix [][]rune, topLeft Location) bool {
+	if topLeft.row < 0 || topLeft.col < 0 || topLeft.row+s.height > len(matrix) {
+		return false
+	}
+	for row := topLeft.row; row < topLeft.row+s.height; row++ {
+		if topLeft.col+s.width > len(matrix[row]) {
+			return false
+		}
+	}
+	for _, cell := range s.cells {
+		if matrix[topLeft.row+cell.offset.row][topLeft.col+cell.offset.col] != cell.char {
+			return false
+		}
+	}
+	return true
+}
+
+// list all placements of the shape in the grid, including placements of its rotations and reflections.
+// placements are ordered by location, then by variant
+func FindShapePlacements(matrix [][]rune, shape Shape) []ShapePlacement {
+	placements := make([]ShapePlacement, 0)
+	variants := shape.Variants()
+
+	for i, row := range matrix {
+		for j := range row {
+			for v, variant := range variants {
+				if variant.MatchesAt(matrix, Location{i, j}) {
+					placements = append(placements, ShapePlacement{Location{i, j}, v})
+				}
+			}
+		}
+	}
+	return placements
+}
+
+func CountShapePlacements(matrix [][]rune, shape Shape) int {
+	return len(FindShapePlacements(matrix, shape))
+}
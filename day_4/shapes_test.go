@@ -0,0 +1,110 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseShape(t *testing.T) {
+	t.Run("cross MAS", func(t *testing.T) {
+		shape, err := ParseShape(CrossMasMask)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shape.height != 3 || shape.width != 3 || len(shape.cells) != 5 {
+			t.Errorf("want 3x3 shape with 5 cells, got %dx%d with %d cells", shape.height, shape.width, len(shape.cells))
+		}
+		if shape.String() != CrossMasMask {
+			t.Errorf("want %q, got %q", CrossMasMask, shape.String())
+		}
+	})
+
+	t.Run("only wildcards", func(t *testing.T) {
+		if _, err := ParseShape("..\n.."); err == nil {
+			t.Errorf("want an error for a mask without cells")
+		}
+	})
+}
+
+func TestShapeVariants(t *testing.T) {
+	testCases := []struct {
+		mask string
+		want int
+	}{
+		{CrossMasMask, 4},
+		{"A", 1},
+		{"AB", 4},
+		{"AB\nA.", 8},
+		{"A.A\n.A.\nA.A", 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.mask, func(t *testing.T) {
+			shape, _ := ParseShape(tc.mask)
+			got := len(shape.Variants())
+			if got != tc.want {
+				t.Errorf("want %d, got %d", tc.want, got)
+			}
+		})
+	}
+
+	t.Run("rotate four times", func(t *testing.T) {
+		shape, _ := ParseShape("AB\nC.")
+		if !shape.Rotate().Rotate().Rotate().Rotate().Equal(shape) {
+			t.Errorf("want the shape back after four rotations")
+		}
+		if got, want := shape.Rotate().String(), "CA\n.B"; got != want {
+			t.Errorf("want %q, got %q", want, got)
+		}
+	})
+}
+
+func TestCountShapePlacements(t *testing.T) {
+	block := `.M.S......
+..A..MSMS.
+.M.S.MAA..
+..A.ASMSM.
+.M.S.M....
+..........
+S.S.S.S.S.
+.A.A.A.A..
+M.M.M.M.M.
+..........`
+	matrix := LinesTo2dSlices(strings.Split(block, "\n"))
+
+	t.Run("same count as CountAllCrossMAS", func(t *testing.T) {
+		shape, _ := ParseShape(CrossMasMask)
+		want := CountAllCrossMAS(matrix)
+		got := CountShapePlacements(matrix, shape)
+		if want != got {
+			t.Errorf("want %d, got %d", want, got)
+		}
+	})
+
+	t.Run("placements of each variant", func(t *testing.T) {
+		shape, _ := ParseShape("M\n.A")
+		matrix := LinesTo2dSlices([]string{"M.M", ".A.", "M.M"})
+		// the variants have the M top left, top right, bottom right and bottom left of the A, each matches once
+		want := []ShapePlacement{
+			{Location{0, 0}, 0},
+			{Location{0, 1}, 1},
+			{Location{1, 0}, 3},
+			{Location{1, 1}, 2},
+		}
+		got := FindShapePlacements(matrix, shape)
+		if !slices.Equal(want, got) {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("wildcards count towards the bounding box", func(t *testing.T) {
+		matrix := LinesTo2dSlices([]string{"A"})
+		for _, mask := range []string{"A.", "A\n.", "A.\n.."} {
+			shape, _ := ParseShape(mask)
+			if got := CountShapePlacements(matrix, shape); got != 0 {
+				t.Errorf("%q: want 0, got %d", mask, got)
+			}
+		}
+	})
+}
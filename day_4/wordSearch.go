@@ -0,0 +1,164 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// the directions a word can be read in the grid, combine them with '|'
+type DirectionSet uint
+
+const (
+	Orthogonal DirectionSet = 1 << iota // left to right, top to bottom
+	Diagonal                            // top-left to bottom-right, top-right to bottom-left
+	Reversed                            // also read each of the chosen directions backwards
+	WrapAround                          // words may continue on the opposite edge of the grid
+
+	AllDirections = Orthogonal | Diagonal | Reversed
+)
+
+type Direction struct {
+	dRow, dCol int
+}
+
+func (d Direction) String() string {
+	return fmt.Sprintf("(%d,%d)", d.dRow, d.dCol)
+}
+
+// a word found in the grid, read from start along direction
+type WordMatch struct {
+	word      string
+	start     Location
+	direction Direction
+}
+
+// expand the direction set into the list of directions to read
+func (ds DirectionSet) Directions() []Direction {
+	directions := make([]Direction, 0, 8)
+	if ds&Orthogonal != 0 {
+		directions = append(directions, Direction{0, 1}, Direction{1, 0})
+	}
+	if ds&Diagonal != 0 {
+		directions = append(directions, Direction{1, 1}, Direction{1, -1})
+	}
+	if ds&Reversed != 0 {
+		for _, d := range slices.Clone(directions) {
+			directions = append(directions, Direction{-d.dRow, -d.dCol})
+		}
+	}
+	return directions
+}
+
+// a line of cells in the grid read along one direction
+type gridLine struct {
+	cells  []Location
+	runes  []rune
+	length int // number of distinct cells; for wrap-around lines the cells after length repeat the beginning of the line
+}
+
+// cut the grid into lines along the direction. Without wrap-around each line starts at the grid edge and ends at the opposite edge,
+// with wrap-around each line is a cycle through the grid, extended by up to 'overlap' cells so that words crossing the end of the cycle are found
+func gridLines(matrix [][]rune, direction Direction, wrapAround bool, overlap int) []gridLine {
+	rows, cols := len(matrix), len(matrix[0])
+	inBounds := func(loc Location) bool {
+		return loc.row >= 0 && loc.row < rows && loc.col >= 0 && loc.col < cols
+	}
+
+	lines := make([]gridLine, 0)
+
+	if !wrapAround {
+		for i := 0; i < rows; i++ {
+			for j := 0; j < cols; j++ {
+				// a line starts at the cell whose predecessor along the direction is outside the grid
+				if inBounds(Location{i - direction.dRow, j - direction.dCol}) {
+					continue
+				}
+				line := gridLine{}
+				for loc := (Location{i, j}); inBounds(loc); loc = (Location{loc.row + direction.dRow, loc.col + direction.dCol}) {
+					line.cells = append(line.cells, loc)
+					line.runes = append(line.runes, matrix[loc.row][loc.col])
+				}
+				line.length = len(line.cells)
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+
+	visited := make([][]bool, rows)
+	for i := range visited {
+		visited[i] = make([]bool, cols)
+	}
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			if visited[i][j] {
+				continue
+			}
+			line := gridLine{}
+			loc := Location{i, j}
+			for !visited[loc.row][loc.col] {
+				visited[loc.row][loc.col] = true
+				line.cells = append(line.cells, loc)
+				line.runes = append(line.runes, matrix[loc.row][loc.col])
+				loc = Location{(loc.row + direction.dRow + rows) % rows, (loc.col + direction.dCol + cols) % cols}
+			}
+			line.length = len(line.cells)
+
+			// repeat the beginning of the cycle, but never reuse a cell within one word
+			for k := 0; k < min(overlap, line.length-1); k++ {
+				line.cells = append(line.cells, line.cells[k])
+				line.runes = append(line.runes, line.runes[k])
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// search the grid for all the words, in all the directions of the direction set.
+// the result is ordered by start location, then direction, then the order of words
+func SearchWords(matrix [][]rune, words []string, directionSet DirectionSet) []WordMatch {
+	matches := make([]WordMatch, 0)
+	if len(matrix) == 0 || len(matrix[0]) == 0 || len(words) == 0 {
+		return matches
+	}
+
+	matcher := NewAhoCorasick(words)
+	wrapAround := directionSet&WrapAround != 0
+
+	type rankedMatch struct {
+		WordMatch
+		directionIndex, wordIndex int
+	}
+	ranked := make([]rankedMatch, 0)
+
+	for directionIndex, direction := range directionSet.Directions() {
+		for _, line := range gridLines(matrix, direction, wrapAround, matcher.MaxWordLength()-1) {
+			matcher.Scan(line.runes, func(wordIndex, start int) {
+				// matches starting in the repeated part of a wrap-around line were already found at their first occurrence
+				if start >= line.length || len(matcher.words[wordIndex]) > line.length {
+					return
+				}
+				ranked = append(ranked, rankedMatch{
+					WordMatch{words[wordIndex], line.cells[start], direction},
+					directionIndex, wordIndex,
+				})
+			})
+		}
+	}
+
+	slices.SortFunc(ranked, func(a, b rankedMatch) int {
+		return cmp.Or(
+			cmp.Compare(a.start.row, b.start.row),
+			cmp.Compare(a.start.col, b.start.col),
+			cmp.Compare(a.directionIndex, b.directionIndex),
+			cmp.Compare(a.wordIndex, b.wordIndex),
+		)
+	})
+
+	for _, match := range ranked {
+		matches = append(matches, match.WordMatch)
+	}
+	return matches
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearchWords(t *testing.T) {
+	block := `MMMSXXMASM
+MSAMXMSMSA
+AMXSXMAAMM
+MSAMASMSMX
+XMASAMXAMM
+XXAMMXXAMA
+SMSMSASXSS
+SAXAMASAAA
+MAMMMXMMMM
+MXMXAXMASX`
+	matrix := LinesTo2dSlices(strings.Split(block, "\n"))
+
+	t.Run("XMAS in all directions", func(t *testing.T) {
+		want := 18
+		got := len(SearchWords(matrix, []string{"XMAS"}, AllDirections))
+		if want != got {
+			t.Errorf("want %d, got %d", want, got)
+		}
+	})
+
+	t.Run("same count as CountAllXMASPatterns", func(t *testing.T) {
+		want := CountAllXMASPatterns(matrix)
+		got := len(SearchWords(matrix, []string{"XMAS"}, AllDirections))
+		if want != got {
+			t.Errorf("want %d, got %d", want, got)
+		}
+	})
+
+	t.Run("several words at once", func(t *testing.T) {
+		xmas := len(SearchWords(matrix, []string{"XMAS"}, AllDirections))
+		sam := len(SearchWords(matrix, []string{"SAM"}, AllDirections))
+		got := len(SearchWords(matrix, []string{"XMAS", "SAM"}, AllDirections))
+		if got != xmas+sam {
+			t.Errorf("want %d, got %d", xmas+sam, got)
+		}
+	})
+}
+
+func TestSearchWordsMatchLocations(t *testing.T) {
+	block := `CAT.
+..A.
+TAC.
+....`
+	matrix := LinesTo2dSlices(strings.Split(block, "\n"))
+
+	t.Run("orthogonal only", func(t *testing.T) {
+		got := SearchWords(matrix, []string{"CAT"}, Orthogonal)
+		want := []WordMatch{{"CAT", Location{0, 0}, Direction{0, 1}}}
+		if len(got) != len(want) || got[0] != want[0] {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("orthogonal and reversed", func(t *testing.T) {
+		got := SearchWords(matrix, []string{"CAT"}, Orthogonal|Reversed)
+		want := []WordMatch{
+			{"CAT", Location{0, 0}, Direction{0, 1}},
+			{"CAT", Location{2, 2}, Direction{0, -1}},
+			{"CAT", Location{2, 2}, Direction{-1, 0}},
+		}
+		if len(got) != len(want) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("want %v, got %v", want[i], got[i])
+			}
+		}
+	})
+}
+
+func TestSearchWordsWrapAround(t *testing.T) {
+	block := `TS.CA
+.....
+.....`
+	matrix := LinesTo2dSlices(strings.Split(block, "\n"))
+
+	t.Run("without wrap around", func(t *testing.T) {
+		got := SearchWords(matrix, []string{"CATS"}, Orthogonal)
+		if len(got) != 0 {
+			t.Errorf("want no match, got %v", got)
+		}
+	})
+
+	t.Run("with wrap around", func(t *testing.T) {
+		got := SearchWords(matrix, []string{"CATS"}, Orthogonal|WrapAround)
+		want := WordMatch{"CATS", Location{0, 3}, Direction{0, 1}}
+		if len(got) != 1 || got[0] != want {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("words longer than the line are not matched by reusing cells", func(t *testing.T) {
+		got := SearchWords(matrix, []string{"CATSCA"}, Orthogonal|WrapAround)
+		if len(got) != 0 {
+			t.Errorf("want no match, got %v", got)
+		}
+	})
+}
@@ -29,6 +29,18 @@ func ConvertToInts(pages []string) []int {
 	return ints
 }
 
+// add the rule 'pre|post', i.e. page pre must be printed before page post
+func AddPageRule(pageRule map[int]Page, pre, post int) {
+	if existingPage, ok := pageRule[post]; !ok {
+		// no such page exists, create a new page
+		pageRule[post] = Page{num: post, predecessors: []int{pre}}
+	} else {
+		// update existing page
+		existingPage.predecessors = append(existingPage.predecessors, pre)
+		pageRule[post] = existingPage
+	}
+}
+
 func IsLegalPageUpdate(pages []int, pageRule map[int]Page) bool {
 	// check if the page update is valid
 	// for each page in the update, check if any pages after it are in its predecessors
@@ -47,25 +59,101 @@ func IsLegalPageUpdate(pages []int, pageRule map[int]Page) bool {
 	return true
 }
 
-func SortPageByRule(pages []int, pageRule map[int]Page) []int {
-	// Use bubble sort to sort the pages
-	for i := 0; i < len(pages)-1; i++ {
-		swapped := false
-
-		for j := 0; j < len(pages)-i-1; j++ {
-			// if page[j+1] is in predecessors of page[j], swap the pages
-			if slices.Contains(pageRule[pages[j]].predecessors, pages[j+1]) {
-				// swap the pages
-				pages[j], pages[j+1] = pages[j+1], pages[j]
-				swapped = true
+// CycleError is returned when the rules between the pages of an update are contradictory
+type CycleError struct {
+	cycle []int // the pages in the cycle, each page must be printed before the next one, and the last before the first
+}
+
+func (e *CycleError) Error() string {
+	pages := make([]string, 0, len(e.cycle)+1)
+	for _, page := range append(e.cycle, e.cycle[0]) {
+		pages = append(pages, strconv.Itoa(page))
+	}
+	return "page rules form a cycle: " + strings.Join(pages, " -> ")
+}
+
+// sort the pages of an update by the rules, using Kahn's algorithm on the rules between the pages in the update.
+// pages which are not constrained relative to each other keep their order in the update.
+// If the rules are contradictory, a *CycleError naming the pages in the cycle is returned
+func SortPageByRule(pages []int, pageRule map[int]Page) ([]int, error) {
+	// position of each page in the update
+	positions := make(map[int]int, len(pages))
+	for i, pageNum := range pages {
+		positions[pageNum] = i
+	}
+
+	// the sub-graph induced by the update: an edge from each predecessor to the page
+	successors := make([][]int, len(pages))
+	predecessors := make([][]int, len(pages))
+	inDegree := make([]int, len(pages))
+	for i, pageNum := range pages {
+		for _, pre := range pageRule[pageNum].predecessors {
+			if j, ok := positions[pre]; ok && j != i {
+				successors[j] = append(successors[j], i)
+				predecessors[i] = append(predecessors[i], j)
+				inDegree[i]++
+			}
+		}
+	}
+
+	// pages ready to be printed, always print the one earliest in the update first
+	ready := make([]int, 0)
+	for i := range pages {
+		if inDegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	sorted := make([]int, 0, len(pages))
+	for len(ready) > 0 {
+		next := slices.Min(ready)
+		ready = slices.DeleteFunc(ready, func(i int) bool { return i == next })
+		sorted = append(sorted, pages[next])
+
+		for _, successor := range successors[next] {
+			inDegree[successor]--
+			if inDegree[successor] == 0 {
+				ready = append(ready, successor)
 			}
 		}
-		if !swapped {
+	}
+
+	if len(sorted) < len(pages) {
+		return nil, &CycleError{findCycle(pages, predecessors, inDegree)}
+	}
+
+	return sorted, nil
+}
+
+// after Kahn's algorithm got stuck, every page left has a predecessor which is also left.
+// walk backwards through these predecessors until a page repeats, that's the cycle
+func findCycle(pages []int, predecessors [][]int, inDegree []int) []int {
+	current := slices.IndexFunc(inDegree, func(d int) bool { return d > 0 })
+
+	walk := make([]int, 0)
+	seenAt := make(map[int]int)
+	for {
+		if start, ok := seenAt[current]; ok {
+			walk = walk[start:]
 			break
 		}
+		seenAt[current] = len(walk)
+		walk = append(walk, current)
+
+		for _, pre := range predecessors[current] {
+			if inDegree[pre] > 0 {
+				current = pre
+				break
+			}
+		}
 	}
 
-	return pages
+	// the walk goes against the rules, reverse it so each page comes before the next
+	cycle := make([]int, len(walk))
+	for i, index := range walk {
+		cycle[len(walk)-1-i] = pages[index]
+	}
+	return cycle
 }
 
 func main() {
@@ -83,16 +171,12 @@ func main() {
 		var pre, post int
 		fmt.Sscanf(line, "%d|%d", &pre, &post)
 
-		if existingPage, ok := pageOrders[post]; !ok {
-			// no such page exists, create a new page
-			pageOrders[post] = Page{num: post, predecessors: []int{pre}}
-		} else {
-			// update existing page
-			existingPage.predecessors = append(existingPage.predecessors, pre)
-			pageOrders[post] = existingPage
-		}
+		AddPageRule(pageOrders, pre, post)
 	}
 
+	analysis := AnalyseRules(pageOrders)
+	fmt.Println("Number of rules: ", len(analysis.rules), "redundant rules: ", len(analysis.redundantRules), "cycles: ", len(analysis.Cycles()))
+
 	// // print the page order rules
 	// for pageNum, page := range pageOrders {
 	// 	fmt.Printf("Page %d: %v\n", pageNum, page.predecessors)
@@ -120,6 +204,11 @@ func main() {
 		// check page update against the page order,
 		validUpdate := IsLegalPageUpdate(pageUpdate, pageOrders)
 		fmt.Println("Page update: ", pageUpdate, "valid: ", validUpdate)
+		if !validUpdate {
+			if explanation, err := ExplainPageUpdate(pageUpdate, pageOrders); err == nil {
+				fmt.Println(explanation)
+			}
+		}
 
 		if validUpdate {
 			validPageUpdates = append(validPageUpdates, pageUpdate)
@@ -138,7 +227,11 @@ func main() {
 	middlePageSumOfCorrections := 0
 	for _, pageUpdate := range invalidPageUpdates {
 		// sort the page update based on the page order
-		sortedPageUpdate := SortPageByRule(pageUpdate, pageOrders)
+		sortedPageUpdate, err := SortPageByRule(pageUpdate, pageOrders)
+		if err != nil {
+			fmt.Println("Page update: ", pageUpdate, "can't be corrected: ", err)
+			continue
+		}
 		middlePageSumOfCorrections += sortedPageUpdate[len(sortedPageUpdate)/2]
 	}
 
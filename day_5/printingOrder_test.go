@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const exampleRules = `47|53
+97|13
+97|61
+97|47
+75|29
+61|13
+75|53
+29|13
+97|29
+53|29
+61|53
+97|53
+61|29
+47|13
+75|47
+97|75
+47|61
+75|61
+47|29
+75|13
+53|13`
+
+func parseRules(t *testing.T, rules string) map[int]Page {
+	t.Helper()
+
+	pageRule := make(map[int]Page)
+	for _, line := range strings.Split(rules, "\n") {
+		pages := ConvertToInts(strings.Split(line, "|"))
+		AddPageRule(pageRule, pages[0], pages[1])
+	}
+	return pageRule
+}
+
+func TestSortPageByRule(t *testing.T) {
+	pageRule := parseRules(t, exampleRules)
+
+	testCases := []struct {
+		update string
+		want   []int
+	}{
+		{"75,97,47,61,53", []int{97, 75, 47, 61, 53}},
+		{"61,13,29", []int{61, 29, 13}},
+		{"97,13,75,29,47", []int{97, 75, 47, 29, 13}},
+		{"75,47,61,53,29", []int{75, 47, 61, 53, 29}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.update, func(t *testing.T) {
+			pages := ConvertToInts(strings.Split(tc.update, ","))
+			got, err := SortPageByRule(pages, pageRule)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("want %v, got %v", tc.want, got)
+			}
+			if !IsLegalPageUpdate(got, pageRule) {
+				t.Errorf("sorted update %v is not legal", got)
+			}
+		})
+	}
+
+	t.Run("unconstrained pages keep their order", func(t *testing.T) {
+		got, _ := SortPageByRule([]int{5, 3, 53, 1, 47}, pageRule)
+		want := []int{5, 3, 1, 47, 53}
+		if !slices.Equal(got, want) {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	})
+}
+
+func TestSortPageByRuleCycle(t *testing.T) {
+	pageRule := parseRules(t, exampleRules+"\n13|75\n1|2")
+
+	_, err := SortPageByRule([]int{1, 2, 75, 47, 13}, pageRule)
+
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("want a CycleError, got %v", err)
+	}
+
+	// the cycle can start at any page, rotate it to start at 75
+	start := slices.Index(cycleErr.cycle, 75)
+	if start < 0 {
+		t.Fatalf("want 75 in the cycle, got %v", cycleErr.cycle)
+	}
+	cycle := append(slices.Clone(cycleErr.cycle[start:]), cycleErr.cycle[:start]...)
+	if !slices.Equal(cycle, []int{75, 13}) && !slices.Equal(cycle, []int{75, 47, 13}) {
+		t.Errorf("want the cycle 75 -> (47 ->) 13, got %v", cycle)
+	}
+	for i, page := range cycle {
+		next := cycle[(i+1)%len(cycle)]
+		if !slices.Contains(pageRule[next].predecessors, page) {
+			t.Errorf("no rule %d|%d in the cycle %v", page, next, cycle)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// a rule 'before|after': page before must be printed before page after
+type Rule struct {
+	before, after int
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%d|%d", r.before, r.after)
+}
+
+func compareRules(a, b Rule) int {
+	return cmp.Or(cmp.Compare(a.before, b.before), cmp.Compare(a.after, b.after))
+}
+
+// the result of analysing the whole rule set
+type RuleAnalysis struct {
+	pages []int  // every page mentioned in a rule, sorted
+	rules []Rule // every rule, sorted, without duplicates
+
+	// the rules left after the transitive reduction, they imply the same orderings as all the rules.
+	// rules between pages of the same strongly connected component are always kept
+	reducedRules []Rule
+	// the rules implied by other rules, i.e. the ones removed by the transitive reduction
+	redundantRules []Rule
+
+	// strongly connected components of the rule graph in topological order, pages in a component with more than one page
+	// are in a cycle and can never be ordered when they appear together in an update. A single page is in a cycle
+	// when it has a rule to itself
+	components [][]int
+}
+
+// pages in a cycle of rules, one slice for each strongly connected component with more than one page, or with a
+// single page and a rule from that page to itself
+func (a RuleAnalysis) Cycles() [][]int {
+	cycles := make([][]int, 0)
+	for _, component := range a.components {
+		if len(component) > 1 || a.hasRule(Rule{component[0], component[0]}) {
+			cycles = append(cycles, component)
+		}
+	}
+	return cycles
+}
+
+func (a RuleAnalysis) hasRule(rule Rule) bool {
+	_, found := slices.BinarySearchFunc(a.rules, rule, compareRules)
+	return found
+}
+
+// pages in the updates which are not mentioned by any rule, they can be printed anywhere
+func (a RuleAnalysis) UnconstrainedPages(updates [][]int) []int {
+	unconstrained := make([]int, 0)
+	for _, update := range updates {
+		for _, page := range update {
+			if _, found := slices.BinarySearch(a.pages, page); !found && !slices.Contains(unconstrained, page) {
+				unconstrained = append(unconstrained, page)
+			}
+		}
+	}
+	slices.Sort(unconstrained)
+	return unconstrained
+}
+
+func AnalyseRules(pageRule map[int]Page) RuleAnalysis {
+	analysis := RuleAnalysis{}
+
+	for pageNum, page := range pageRule {
+		analysis.pages = append(analysis.pages, pageNum)
+		for _, pre := range page.predecessors {
+			analysis.pages = append(analysis.pages, pre)
+			analysis.rules = append(analysis.rules, Rule{pre, pageNum})
+		}
+	}
+	slices.Sort(analysis.pages)
+	analysis.pages = slices.Compact(analysis.pages)
+	slices.SortFunc(analysis.rules, compareRules)
+	analysis.rules = slices.Compact(analysis.rules)
+
+	// successors of each page, in sorted order because the rules are sorted
+	successors := make(map[int][]int)
+	for _, rule := range analysis.rules {
+		successors[rule.before] = append(successors[rule.before], rule.after)
+	}
+
+	analysis.components = stronglyConnectedComponents(analysis.pages, successors)
+	componentOf := make(map[int]int)
+	for c, component := range analysis.components {
+		for _, page := range component {
+			componentOf[page] = c
+		}
+	}
+
+	// the condensation: an edge between two components when a rule goes from one to the other
+	componentSuccessors := make([][]int, len(analysis.components))
+	for _, rule := range analysis.rules {
+		from, to := componentOf[rule.before], componentOf[rule.after]
+		if from != to && !slices.Contains(componentSuccessors[from], to) {
+			componentSuccessors[from] = append(componentSuccessors[from], to)
+		}
+	}
+
+	// components reachable from each component, components are in topological order so walk them backwards
+	reachable := make([][]bool, len(analysis.components))
+	for c := len(analysis.components) - 1; c >= 0; c-- {
+		reachable[c] = make([]bool, len(analysis.components))
+		for _, next := range componentSuccessors[c] {
+			reachable[c][next] = true
+			for k, ok := range reachable[next] {
+				reachable[c][k] = reachable[c][k] || ok
+			}
+		}
+	}
+
+	// a rule between two components is redundant when the other component is reachable through a third component,
+	// or when an earlier rule already connects the same two components
+	connected := make(map[[2]int]bool)
+	for _, rule := range analysis.rules {
+		from, to := componentOf[rule.before], componentOf[rule.after]
+		if from == to {
+			analysis.reducedRules = append(analysis.reducedRules, rule)
+			continue
+		}
+
+		implied := connected[[2]int{from, to}]
+		for _, next := range componentSuccessors[from] {
+			if next != to && reachable[next][to] {
+				implied = true
+				break
+			}
+		}
+
+		if implied {
+			analysis.redundantRules = append(analysis.redundantRules, rule)
+		} else {
+			analysis.reducedRules = append(analysis.reducedRules, rule)
+			connected[[2]int{from, to}] = true
+		}
+	}
+
+	return analysis
+}
+
+// Tarjan's algorithm. The components are returned in topological order, with the pages in each component sorted
+func stronglyConnectedComponents(pages []int, successors map[int][]int) [][]int {
+	index := make(map[int]int)
+	lowLink := make(map[int]int)
+	onStack := make(map[int]bool)
+	stack := make([]int, 0)
+	components := make([][]int, 0)
+
+	var visit func(page int)
+	visit = func(page int) {
+		index[page] = len(index)
+		lowLink[page] = index[page]
+		stack = append(stack, page)
+		onStack[page] = true
+
+		for _, next := range successors[page] {
+			if _, visited := index[next]; !visited {
+				visit(next)
+				lowLink[page] = min(lowLink[page], lowLink[next])
+			} else if onStack[next] {
+				lowLink[page] = min(lowLink[page], index[next])
+			}
+		}
+
+		// page is the root of a component, pop the component from the stack
+		if lowLink[page] == index[page] {
+			component := make([]int, 0)
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == page {
+					break
+				}
+			}
+			slices.Sort(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, page := range pages {
+		if _, visited := index[page]; !visited {
+			visit(page)
+		}
+	}
+
+	// Tarjan finds the components in reverse topological order
+	slices.Reverse(components)
+	return components
+}
+
+// write the rule graph in Graphviz DOT format. Redundant rules are drawn dashed,
+// and pages in a cycle are grouped in a cluster. The first write error is returned
+func WriteDOT(w io.Writer, analysis RuleAnalysis) error {
+	// a bufio.Writer stops writing after the first error and returns it on Flush
+	writer := bufio.NewWriter(w)
+	fmt.Fprintln(writer, "digraph rules {")
+
+	for c, cycle := range analysis.Cycles() {
+		fmt.Fprintf(writer, "  subgraph cluster_%d {\n    label=\"cycle %d\";\n    color=red;\n", c, c)
+		for _, page := range cycle {
+			fmt.Fprintf(writer, "    %d;\n", page)
+		}
+		fmt.Fprintln(writer, "  }")
+	}
+
+	for _, page := range analysis.pages {
+		fmt.Fprintf(writer, "  %d;\n", page)
+	}
+
+	for _, rule := range analysis.reducedRules {
+		fmt.Fprintf(writer, "  %d -> %d;\n", rule.before, rule.after)
+	}
+	for _, rule := range analysis.redundantRules {
+		fmt.Fprintf(writer, "  %d -> %d [style=dashed, color=gray];\n", rule.before, rule.after)
+	}
+
+	fmt.Fprintln(writer, "}")
+	return writer.Flush()
+}
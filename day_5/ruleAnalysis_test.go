@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAnalyseRules(t *testing.T) {
+	t.Run("transitive reduction of the example", func(t *testing.T) {
+		analysis := AnalyseRules(parseRules(t, exampleRules))
+
+		// the example rules form a total order 97, 75, 47, 61, 53, 29, 13
+		want := []Rule{{29, 13}, {47, 61}, {53, 29}, {61, 53}, {75, 47}, {97, 75}}
+		if !slices.Equal(analysis.reducedRules, want) {
+			t.Errorf("want %v, got %v", want, analysis.reducedRules)
+		}
+		if len(analysis.redundantRules) != 21-len(want) {
+			t.Errorf("want %d redundant rules, got %d", 21-len(want), len(analysis.redundantRules))
+		}
+		if len(analysis.Cycles()) != 0 {
+			t.Errorf("want no cycles, got %v", analysis.Cycles())
+		}
+	})
+
+	t.Run("cycles", func(t *testing.T) {
+		analysis := AnalyseRules(parseRules(t, "1|2\n2|3\n3|1\n3|4\n1|4\n4|5"))
+
+		wantCycles := [][]int{{1, 2, 3}}
+		if !slices.EqualFunc(analysis.Cycles(), wantCycles, slices.Equal) {
+			t.Errorf("want %v, got %v", wantCycles, analysis.Cycles())
+		}
+
+		// 1|4 and 3|4 connect the same two components, only one of them is needed
+		wantRedundant := []Rule{{3, 4}}
+		if !slices.Equal(analysis.redundantRules, wantRedundant) {
+			t.Errorf("want %v, got %v", wantRedundant, analysis.redundantRules)
+		}
+
+		wantComponents := [][]int{{1, 2, 3}, {4}, {5}}
+		if !slices.EqualFunc(analysis.components, wantComponents, slices.Equal) {
+			t.Errorf("want %v, got %v", wantComponents, analysis.components)
+		}
+	})
+
+	t.Run("self loop", func(t *testing.T) {
+		analysis := AnalyseRules(parseRules(t, "5|5\n5|6"))
+
+		wantCycles := [][]int{{5}}
+		if !slices.EqualFunc(analysis.Cycles(), wantCycles, slices.Equal) {
+			t.Errorf("want %v, got %v", wantCycles, analysis.Cycles())
+		}
+	})
+
+	t.Run("unconstrained pages", func(t *testing.T) {
+		analysis := AnalyseRules(parseRules(t, exampleRules))
+
+		got := analysis.UnconstrainedPages([][]int{{75, 1, 47}, {2, 1, 13}})
+		want := []int{1, 2}
+		if !slices.Equal(got, want) {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	})
+}
+
+func TestWriteDOT(t *testing.T) {
+	analysis := AnalyseRules(parseRules(t, "1|2\n2|1\n2|3\n1|3"))
+
+	var sb strings.Builder
+	if err := WriteDOT(&sb, analysis); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dot := sb.String()
+
+	for _, want := range []string{
+		"digraph rules {",
+		"subgraph cluster_0 {",
+		"  1 -> 2;\n",
+		"  2 -> 1;\n",
+		"  1 -> 3;\n",
+		"  2 -> 3 [style=dashed, color=gray];\n",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("want %q in\n%s", want, dot)
+		}
+	}
+}
+
+// a writer which fails after accepting limit bytes
+type failingWriter struct {
+	limit int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, errors.New("disk full")
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+func TestWriteDOTError(t *testing.T) {
+	analysis := AnalyseRules(parseRules(t, exampleRules))
+
+	for _, limit := range []int{0, 10, 100} {
+		if err := WriteDOT(&failingWriter{limit}, analysis); err == nil || err.Error() != "disk full" {
+			t.Errorf("limit %d: want the write error, got %v", limit, err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// a rule broken by an update: rule.before is printed at beforePosition, after rule.after at afterPosition
+type RuleViolation struct {
+	rule           Rule
+	beforePosition int
+	afterPosition  int
+}
+
+func (v RuleViolation) String() string {
+	return fmt.Sprintf("%v (%d at position %d, %d at position %d)", v.rule, v.rule.before, v.beforePosition, v.rule.after, v.afterPosition)
+}
+
+// move a page from its position in the update to its position in the corrected update
+type PageMove struct {
+	page, from, to int
+}
+
+func (m PageMove) String() string {
+	return fmt.Sprintf("move %d from position %d to %d", m.page, m.from, m.to)
+}
+
+// why an update is illegal, and how to fix it
+type UpdateExplanation struct {
+	violations []RuleViolation
+	// the fewest pages to move to make the update legal. Take all the moved pages out of the update,
+	// then put each one back at its 'to' position, in order
+	moves []PageMove
+	// the update after the moves
+	corrected []int
+}
+
+func (e UpdateExplanation) String() string {
+	if len(e.violations) == 0 {
+		return "legal"
+	}
+
+	lines := make([]string, 0, len(e.violations)+len(e.moves))
+	for _, violation := range e.violations {
+		lines = append(lines, "violates "+violation.String())
+	}
+	for _, move := range e.moves {
+		lines = append(lines, move.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// find every rule broken by the update, ordered by the position of the page printed too early (the page after in
+// the rule), then by the position of the page printed too late
+func FindRuleViolations(pages []int, pageRule map[int]Page) []RuleViolation {
+	violations := make([]RuleViolation, 0)
+	for i, pageNum := range pages {
+		if currentPage, ok := pageRule[pageNum]; ok {
+			for j := i + 1; j < len(pages); j++ {
+				if slices.Contains(currentPage.predecessors, pages[j]) {
+					violations = append(violations, RuleViolation{Rule{pages[j], pageNum}, j, i})
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// explain why the update is illegal: the rules it violates, and the fewest page moves that make it legal.
+// If the rules between the pages of the update are contradictory, a *CycleError is returned
+func ExplainPageUpdate(pages []int, pageRule map[int]Page) (UpdateExplanation, error) {
+	explanation := UpdateExplanation{violations: FindRuleViolations(pages, pageRule)}
+
+	if _, err := SortPageByRule(pages, pageRule); err != nil {
+		return explanation, err
+	}
+
+	// the pages which stay where they are: the largest set of pages without any pair in the wrong order
+	kept := largestConsistentSubset(pages, pageRule)
+
+	// sort again, with extra rules keeping the kept pages in their current order
+	keepingRule := make(map[int]Page, len(pages))
+	for _, pageNum := range pages {
+		keepingRule[pageNum] = Page{pageNum, slices.Clone(pageRule[pageNum].predecessors)}
+	}
+	for k := 1; k < len(kept); k++ {
+		AddPageRule(keepingRule, pages[kept[k-1]], pages[kept[k]])
+	}
+	corrected, err := SortPageByRule(pages, keepingRule)
+	if err != nil {
+		return explanation, err
+	}
+	explanation.corrected = corrected
+
+	for from, pageNum := range pages {
+		if !slices.Contains(kept, from) {
+			explanation.moves = append(explanation.moves, PageMove{pageNum, from, slices.Index(corrected, pageNum)})
+		}
+	}
+	slices.SortFunc(explanation.moves, func(a, b PageMove) int { return a.to - b.to })
+
+	return explanation, nil
+}
+
+// positions in the update of the largest set of pages that are pairwise in the right order, taking into account
+// rules implied by other rules. These pages don't need to move.
+//
+// Two positions i < j are inverted when pages[j] must come before pages[i]. Inversion is a partial order on positions,
+// so the largest set without inverted pairs is its largest antichain, which is found with Dilworth's
+// theorem from a maximum bipartite matching.
+func largestConsistentSubset(pages []int, pageRule map[int]Page) []int {
+	n := len(pages)
+
+	// mustPrecede[a][b]: the page at position a must be printed before the page at position b
+	mustPrecede := make([][]bool, n)
+	for a := range mustPrecede {
+		mustPrecede[a] = make([]bool, n)
+	}
+	for a, pageNum := range pages {
+		for b := range pages {
+			if a != b && slices.Contains(pageRule[pageNum].predecessors, pages[b]) {
+				mustPrecede[b][a] = true
+			}
+		}
+	}
+	// transitive closure, Floyd-Warshall style
+	for k := range n {
+		for a := range n {
+			for b := range n {
+				mustPrecede[a][b] = mustPrecede[a][b] || (mustPrecede[a][k] && mustPrecede[k][b])
+			}
+		}
+	}
+
+	inverted := func(i, j int) bool {
+		return i < j && mustPrecede[j][i]
+	}
+
+	// maximum matching between a left and a right copy of the positions, with Kuhn's augmenting paths
+	matchOfRight := make([]int, n)
+	matchOfLeft := make([]int, n)
+	for i := range n {
+		matchOfRight[i], matchOfLeft[i] = -1, -1
+	}
+	var augment func(left int, seen []bool) bool
+	augment = func(left int, seen []bool) bool {
+		for right := range n {
+			if inverted(left, right) && !seen[right] {
+				seen[right] = true
+				if matchOfRight[right] == -1 || augment(matchOfRight[right], seen) {
+					matchOfRight[right] = left
+					matchOfLeft[left] = right
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for left := range n {
+		augment(left, make([]bool, n))
+	}
+
+	// König's theorem: walk alternating paths from the unmatched left positions. The largest antichain are
+	// the positions reached on the left but not on the right
+	reachedLeft := make([]bool, n)
+	reachedRight := make([]bool, n)
+	queue := make([]int, 0)
+	for left := range n {
+		if matchOfLeft[left] == -1 {
+			reachedLeft[left] = true
+			queue = append(queue, left)
+		}
+	}
+	for len(queue) > 0 {
+		left := queue[0]
+		queue = queue[1:]
+		for right := range n {
+			if inverted(left, right) && !reachedRight[right] {
+				reachedRight[right] = true
+				if next := matchOfRight[right]; next != -1 && !reachedLeft[next] {
+					reachedLeft[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	kept := make([]int, 0)
+	for i := range n {
+		if reachedLeft[i] && !reachedRight[i] {
+			kept = append(kept, i)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindRuleViolations(t *testing.T) {
+	pageRule := parseRules(t, exampleRules)
+
+	got := FindRuleViolations([]int{61, 13, 29}, pageRule)
+	want := []RuleViolation{{Rule{29, 13}, 2, 1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if got := FindRuleViolations([]int{75, 47, 61, 53, 29}, pageRule); len(got) != 0 {
+		t.Errorf("want no violations, got %v", got)
+	}
+}
+
+func TestExplainPageUpdate(t *testing.T) {
+	pageRule := parseRules(t, exampleRules)
+
+	testCases := []struct {
+		update     string
+		violations int
+		moves      int
+	}{
+		{"75,47,61,53,29", 0, 0},
+		{"75,97,47,61,53", 1, 1},
+		{"61,13,29", 1, 1},
+		{"97,13,75,29,47", 4, 2},
+		{"13,29,53,61,47,75,97", 21, 6},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.update, func(t *testing.T) {
+			pages := ConvertToInts(strings.Split(tc.update, ","))
+			explanation, err := ExplainPageUpdate(pages, pageRule)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(explanation.violations) != tc.violations {
+				t.Errorf("want %d violations, got %v", tc.violations, explanation.violations)
+			}
+			if len(explanation.moves) != tc.moves {
+				t.Errorf("want %d moves, got %v", tc.moves, explanation.moves)
+			}
+			if !IsLegalPageUpdate(explanation.corrected, pageRule) {
+				t.Errorf("corrected update %v is not legal", explanation.corrected)
+			}
+
+			// apply the moves: take the moved pages out, then put them back at their new positions
+			moved := slices.Clone(pages)
+			for _, move := range explanation.moves {
+				moved = slices.DeleteFunc(moved, func(page int) bool { return page == move.page })
+			}
+			for _, move := range explanation.moves {
+				moved = slices.Insert(moved, move.to, move.page)
+			}
+			if !slices.Equal(moved, explanation.corrected) {
+				t.Errorf("moves give %v, want %v", moved, explanation.corrected)
+			}
+		})
+	}
+
+	t.Run("rules implied through other pages", func(t *testing.T) {
+		// 3 must come before 1 through 2, so 1 and 3 can't both stay
+		pageRule := parseRules(t, "3|2\n2|1")
+		explanation, _ := ExplainPageUpdate([]int{1, 3, 2}, pageRule)
+		if len(explanation.moves) != 1 {
+			t.Errorf("want 1 move, got %v", explanation.moves)
+		}
+		if want := []int{3, 2, 1}; !slices.Equal(explanation.corrected, want) {
+			t.Errorf("want %v, got %v", want, explanation.corrected)
+		}
+	})
+
+	t.Run("contradictory rules", func(t *testing.T) {
+		pageRule := parseRules(t, "1|2\n2|1")
+		explanation, err := ExplainPageUpdate([]int{1, 2}, pageRule)
+
+		var cycleErr *CycleError
+		if !errors.As(err, &cycleErr) {
+			t.Errorf("want a CycleError, got %v", err)
+		}
+		if len(explanation.violations) != 1 {
+			t.Errorf("want 1 violation, got %v", explanation.violations)
+		}
+	})
+}
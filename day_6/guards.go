@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// the symbols of a guard on the map, pointing in the guard's initial direction
+const GuardGlyphs = "^>v<"
+
+// what a guard does when it meets an obstacle
+type TurnPolicy int
+
+const (
+	TurnRight TurnPolicy = iota
+	TurnLeft
+	TurnAround
+)
+
+var previousDirections = map[rune]rune{
+	'^': '<',
+	'>': '^',
+	'v': '>',
+	'<': 'v',
+}
+
+var oppositeDirections = map[rune]rune{
+	'^': 'v',
+	'>': '<',
+	'v': '^',
+	'<': '>',
+}
+
+func (p TurnPolicy) Turn(direction rune) rune {
+	switch p {
+	case TurnLeft:
+		return previousDirections[direction]
+	case TurnAround:
+		return oppositeDirections[direction]
+	}
+	return nextDirections[direction]
+}
+
+func (p TurnPolicy) String() string {
+	return [...]string{"right", "left", "around"}[p]
+}
+
+// one of the guards patrolling the map
+type PatrollingGuard struct {
+	id         int // the guards are numbered in reading order of the map
+	location   Location
+	direction  rune
+	turnPolicy TurnPolicy
+
+	route      []VisitingRecord
+	records    map[VisitingRecord]bool
+	leftMap    bool
+	loopFormed bool
+}
+
+func (g *PatrollingGuard) String() string {
+	return fmt.Sprintf("guard %d at %v facing %c", g.id, g.location, g.direction)
+}
+
+// two guards meet, either on the same location, or by walking through each other
+type GuardCollision struct {
+	step     int
+	guards   [2]int
+	location Location // for guards walking through each other, the location the first guard moved to
+	swapped  bool
+}
+
+// find all the guards on the map, any of the GuardGlyphs is a guard facing that direction
+func FindGuards(matrix [][]rune, turnPolicy TurnPolicy) []*PatrollingGuard {
+	guards := make([]*PatrollingGuard, 0)
+	for i, row := range matrix {
+		for j, cell := range row {
+			if strings.ContainsRune(GuardGlyphs, cell) {
+				guards = append(guards, &PatrollingGuard{
+					id:         len(guards),
+					location:   Location{i, j},
+					direction:  cell,
+					turnPolicy: turnPolicy,
+					records:    make(map[VisitingRecord]bool),
+				})
+			}
+		}
+	}
+	return guards
+}
+
+// the guard makes one step: it turns if there is an obstacle ahead, otherwise it moves forward, possibly off the map
+func (g *PatrollingGuard) step(matrix [][]rune) {
+	record := VisitingRecord{g.location, g.direction}
+	g.route = append(g.route, record)
+
+	// the same location in the same direction means the guard is walking in a loop
+	if g.records[record] {
+		g.loopFormed = true
+	}
+	g.records[record] = true
+
+	delta := directionDeltas[strings.IndexRune(GuardGlyphs, g.direction)]
+	ahead := Location{g.location.row + delta[0], g.location.col + delta[1]}
+
+	if ahead.row < 0 || ahead.row >= len(matrix) || ahead.col < 0 || ahead.col >= len(matrix[ahead.row]) {
+		g.leftMap = true
+		return
+	}
+
+	if matrix[ahead.row][ahead.col] == Obstacle {
+		g.direction = g.turnPolicy.Turn(g.direction)
+	} else {
+		g.location = ahead
+	}
+}
+
+// all the guards patrol the map at the same time, one step each per round. Guards don't block each other, but the times
+// they meet are returned. The patrol ends once every guard has left the map or is known to walk in a loop;
+// guards in a loop keep walking until then, so they can still run into the others
+func PatrolGuards(matrix [][]rune, guards []*PatrollingGuard) (collisions []GuardCollision) {
+	collisions = make([]GuardCollision, 0)
+
+	for step := 1; ; step++ {
+		active := make([]*PatrollingGuard, 0, len(guards))
+		for _, guard := range guards {
+			if !guard.leftMap {
+				active = append(active, guard)
+			}
+		}
+
+		done := true
+		for _, guard := range active {
+			if !guard.loopFormed {
+				done = false
+			}
+		}
+		if done {
+			return
+		}
+
+		previousLocations := make([]Location, len(active))
+		for i, guard := range active {
+			previousLocations[i] = guard.location
+			guard.step(matrix)
+		}
+
+		for i, a := range active {
+			for j := i + 1; j < len(active); j++ {
+				b := active[j]
+				if a.leftMap || b.leftMap {
+					continue
+				}
+				if a.location == b.location {
+					collisions = append(collisions, GuardCollision{step, [2]int{a.id, b.id}, a.location, false})
+				} else if a.location == previousLocations[j] && b.location == previousLocations[i] {
+					collisions = append(collisions, GuardCollision{step, [2]int{a.id, b.id}, a.location, true})
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func parseMap(mapStr string) [][]rune {
+	matrix := make([][]rune, 0)
+	for _, line := range strings.Split(mapStr, "\n") {
+		matrix = append(matrix, []rune(line))
+	}
+	return matrix
+}
+
+func TestTurnPolicy(t *testing.T) {
+	testCases := []struct {
+		policy    TurnPolicy
+		direction rune
+		want      rune
+	}{
+		{TurnRight, '^', '>'},
+		{TurnRight, '<', '^'},
+		{TurnLeft, '^', '<'},
+		{TurnLeft, 'v', '>'},
+		{TurnAround, '>', '<'},
+		{TurnAround, 'v', '^'},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.policy.String()+string(tc.direction), func(t *testing.T) {
+			got := tc.policy.Turn(tc.direction)
+			if got != tc.want {
+				t.Errorf("want %c, got %c", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPatrolGuards(t *testing.T) {
+	t.Run("single guard like Patrol", func(t *testing.T) {
+		matrix := parseMap(`....#.....
+.........#
+..........
+..#.......
+.......#..
+..........
+.#..^.....
+........#.
+#.........
+......#...`)
+
+		guards := FindGuards(matrix, TurnRight)
+		collisions := PatrolGuards(matrix, guards)
+
+		if len(guards) != 1 || len(collisions) != 0 {
+			t.Fatalf("want 1 guard and no collisions, got %v and %v", guards, collisions)
+		}
+		want := 41
+		got := len(GetUniqueLocations(guards[0].route))
+		if want != got {
+			t.Errorf("want %d, got %d", want, got)
+		}
+		if !guards[0].leftMap || guards[0].loopFormed {
+			t.Errorf("want the guard to leave the map")
+		}
+	})
+
+	t.Run("turning left", func(t *testing.T) {
+		matrix := parseMap(`.#...
+.....
+.^...`)
+		guards := FindGuards(matrix, TurnLeft)
+		PatrolGuards(matrix, guards)
+
+		want := []Location{{2, 1}, {1, 1}, {1, 0}}
+		got := GetUniqueLocations(guards[0].route)
+		if !slices.Equal(got, want) {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("turning around forms a loop", func(t *testing.T) {
+		matrix := parseMap(`#>.#`)
+		guards := FindGuards(matrix, TurnAround)
+		PatrolGuards(matrix, guards)
+
+		if !guards[0].loopFormed || guards[0].leftMap {
+			t.Errorf("want the guard in a loop")
+		}
+	})
+
+	t.Run("guards meeting on the same location", func(t *testing.T) {
+		matrix := parseMap(`>...<`)
+		guards := FindGuards(matrix, TurnRight)
+		collisions := PatrolGuards(matrix, guards)
+
+		want := []GuardCollision{{2, [2]int{0, 1}, Location{0, 2}, false}}
+		if !slices.Equal(collisions, want) {
+			t.Errorf("want %v, got %v", want, collisions)
+		}
+	})
+
+	t.Run("guards walking through each other", func(t *testing.T) {
+		matrix := parseMap(`.><.`)
+		guards := FindGuards(matrix, TurnRight)
+		collisions := PatrolGuards(matrix, guards)
+
+		want := []GuardCollision{{1, [2]int{0, 1}, Location{0, 2}, true}}
+		if !slices.Equal(collisions, want) {
+			t.Errorf("want %v, got %v", want, collisions)
+		}
+	})
+}
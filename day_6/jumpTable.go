@@ -0,0 +1,181 @@
+package main
+
+// the row and column steps of the directions '^', '>', 'v' and '<', a direction index is a position in this array
+var directionDeltas = [4][2]int{{-1, 0}, {0, 1}, {1, 0}, {0, -1}}
+
+// JumpTable saves, for every cell and direction, where a guard walking from that cell stops in front of the next obstacle.
+// Cells are numbered row by row
+type JumpTable struct {
+	rows, cols int
+	obstacles  Bitset
+	next       [4][]int // the cell in front of the next obstacle, or -1 if the guard walks off the map
+}
+
+func NewJumpTable(matrix [][]rune) *JumpTable {
+	jt := &JumpTable{rows: len(matrix), cols: len(matrix[0])}
+	jt.obstacles = NewBitset(jt.rows * jt.cols)
+	for i, row := range matrix {
+		for j, cell := range row {
+			if cell == Obstacle {
+				jt.obstacles.Set(i*jt.cols + j)
+			}
+		}
+	}
+
+	for d := range jt.next {
+		jt.next[d] = make([]int, jt.rows*jt.cols)
+	}
+
+	// sweep each row and column against the walking direction, remembering the cell in front of the last obstacle seen
+	for i := 0; i < jt.rows; i++ {
+		stop := -1
+		for j := 0; j < jt.cols; j++ { // walking left
+			if jt.obstacles.Has(i*jt.cols + j) {
+				stop = i*jt.cols + j + 1
+			} else {
+				jt.next[3][i*jt.cols+j] = stop
+			}
+		}
+		stop = -1
+		for j := jt.cols - 1; j >= 0; j-- { // walking right
+			if jt.obstacles.Has(i*jt.cols + j) {
+				stop = i*jt.cols + j - 1
+			} else {
+				jt.next[1][i*jt.cols+j] = stop
+			}
+		}
+	}
+	for j := 0; j < jt.cols; j++ {
+		stop := -1
+		for i := 0; i < jt.rows; i++ { // walking up
+			if jt.obstacles.Has(i*jt.cols + j) {
+				stop = (i+1)*jt.cols + j
+			} else {
+				jt.next[0][i*jt.cols+j] = stop
+			}
+		}
+		stop = -1
+		for i := jt.rows - 1; i >= 0; i-- { // walking down
+			if jt.obstacles.Has(i*jt.cols + j) {
+				stop = (i-1)*jt.cols + j
+			} else {
+				jt.next[2][i*jt.cols+j] = stop
+			}
+		}
+	}
+
+	return jt
+}
+
+func (jt *JumpTable) cell(loc Location) int {
+	return loc.row*jt.cols + loc.col
+}
+
+func (jt *JumpTable) location(cell int) Location {
+	return Location{cell / jt.cols, cell % jt.cols}
+}
+
+// the number of steps from cell 'from' to cell 'to' walking in direction d, or -1 if 'to' is not ahead of 'from'
+func (jt *JumpTable) stepsTo(from, to, d int) int {
+	fromLoc, toLoc := jt.location(from), jt.location(to)
+	dRow, dCol := toLoc.row-fromLoc.row, toLoc.col-fromLoc.col
+	switch {
+	case directionDeltas[d][0] == 0 && dRow == 0 && dCol*directionDeltas[d][1] > 0:
+		return dCol * directionDeltas[d][1]
+	case directionDeltas[d][1] == 0 && dCol == 0 && dRow*directionDeltas[d][0] > 0:
+		return dRow * directionDeltas[d][0]
+	}
+	return -1
+}
+
+// check if the guard, starting at cell 'start' and walking in direction d, patrols in a loop when there is an extra obstacle at cell 'obstacle'.
+// visited must be a Bitset of size 4*rows*cols with no bits set, it is cleared again before returning
+func (jt *JumpTable) FormsLoop(start, d, obstacle int, visited Bitset) (loopFormed bool) {
+	touched := make([]int, 0, 16)
+	defer func() {
+		for _, state := range touched {
+			visited.Clear(state)
+		}
+	}()
+
+	current := start
+	for {
+		// the guard only needs to be tracked where it turns, a loop means the same turn is made twice
+		state := current*4 + d
+		if visited.Has(state) {
+			return true
+		}
+		visited.Set(state)
+		touched = append(touched, state)
+
+		stop := jt.next[d][current]
+
+		// the extra obstacle is hit before the original one
+		if steps := jt.stepsTo(current, obstacle, d); steps > 0 && (stop == -1 || steps <= jt.stepsTo(current, stop, d)) {
+			stop = obstacle - (directionDeltas[d][0]*jt.cols + directionDeltas[d][1])
+		}
+
+		if stop == -1 {
+			return false // walked off the map
+		}
+
+		current = stop
+		d = (d + 1) % 4
+	}
+}
+
+// a place to put an extra obstacle, and where the guard is when it first bumps into it
+type obstructionCandidate struct {
+	location       Location
+	guardCell      int
+	guardDirection int
+}
+
+// walk the guard's original route step by step. Every cell the guard is about to enter for the first time is a candidate for an extra obstacle,
+// the loop check for it can start right where the guard is, because the route up to here is unchanged by the obstacle
+func (jt *JumpTable) obstructionCandidates(guardLocation Location) []obstructionCandidate {
+	candidates := make([]obstructionCandidate, 0)
+
+	entered := NewBitset(jt.rows * jt.cols)
+	visited := NewBitset(4 * jt.rows * jt.cols)
+	current, d := jt.cell(guardLocation), 0
+	entered.Set(current)
+
+	for {
+		// the original route may already be a loop
+		if visited.Has(current*4 + d) {
+			return candidates
+		}
+		visited.Set(current*4 + d)
+
+		loc := jt.location(current)
+		ahead := Location{loc.row + directionDeltas[d][0], loc.col + directionDeltas[d][1]}
+		if ahead.row < 0 || ahead.row >= jt.rows || ahead.col < 0 || ahead.col >= jt.cols {
+			return candidates
+		}
+
+		next := jt.cell(ahead)
+		if jt.obstacles.Has(next) {
+			d = (d + 1) % 4
+			continue
+		}
+
+		if !entered.Has(next) {
+			entered.Set(next)
+			candidates = append(candidates, obstructionCandidate{ahead, current, d})
+		}
+		current = next
+	}
+}
+
+// find the guard's initial location
+func FindGuard(matrix [][]rune) Location {
+	for i, row := range matrix {
+		for j, cell := range row {
+			if cell == Guard {
+				return Location{i, j}
+			}
+		}
+	}
+	return Location{}
+}
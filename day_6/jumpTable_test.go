@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestNewJumpTable(t *testing.T) {
+	mapStr := `.#..
+....
+#..#
+..^.`
+	matrix := make([][]rune, 0)
+	for _, line := range strings.Split(mapStr, "\n") {
+		matrix = append(matrix, []rune(line))
+	}
+	jt := NewJumpTable(matrix)
+
+	testCases := []struct {
+		name      string
+		from      Location
+		direction int
+		want      int
+	}{
+		{"up to obstacle", Location{3, 1}, 0, jt.cell(Location{1, 1})},
+		{"up off the map", Location{3, 2}, 0, -1},
+		{"right to obstacle", Location{2, 1}, 1, jt.cell(Location{2, 2})},
+		{"down to obstacle", Location{0, 0}, 2, jt.cell(Location{1, 0})},
+		{"down off the map", Location{0, 2}, 2, -1},
+		{"left off the map", Location{1, 3}, 3, -1},
+		{"obstacle right ahead", Location{2, 2}, 1, jt.cell(Location{2, 2})},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := jt.next[tc.direction][jt.cell(tc.from)]
+			if got != tc.want {
+				t.Errorf("want %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestGetPatrolLoopOpportunitiesRandomMaps(t *testing.T) {
+	random := rand.New(rand.NewSource(2024))
+
+	for n := 0; n < 50; n++ {
+		rows, cols := 5+random.Intn(20), 5+random.Intn(20)
+		matrix := make([][]rune, rows)
+		for i := range matrix {
+			matrix[i] = make([]rune, cols)
+			for j := range matrix[i] {
+				matrix[i][j] = '.'
+				if random.Intn(8) == 0 {
+					matrix[i][j] = Obstacle
+				}
+			}
+		}
+		matrix[random.Intn(rows)][random.Intn(cols)] = Guard
+
+		want := GetPatrolLoopOpportunitiesBruteForce(matrix)
+		got := GetPatrolLoopOpportunities(matrix)
+		if want != got {
+			t.Fatalf("want %d, got %d for map\n%s", want, got, matrixString(matrix))
+		}
+	}
+}
+
+func matrixString(matrix [][]rune) string {
+	lines := make([]string, len(matrix))
+	for i, row := range matrix {
+		lines[i] = string(row)
+	}
+	return strings.Join(lines, "\n")
+}
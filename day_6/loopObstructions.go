@@ -0,0 +1,47 @@
+package main
+
+import (
+	"sync"
+)
+
+// find the locations where an extra obstacle makes the guard patrol in a loop. The candidate obstacles are independent,
+// so they are checked by a pool of workers. The locations are in the order the guard reaches them on its original route,
+// whatever the number of workers
+func FindLoopObstructions(matrix [][]rune, workers int) []Location {
+	workers = max(workers, 1)
+
+	jt := NewJumpTable(matrix)
+	candidates := jt.obstructionCandidates(FindGuard(matrix))
+
+	// each worker writes only the results of its own candidates, so no locking is needed
+	loopFormed := make([]bool, len(candidates))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			visited := NewBitset(4 * jt.rows * jt.cols)
+			for i := range jobs {
+				candidate := candidates[i]
+				loopFormed[i] = jt.FormsLoop(candidate.guardCell, candidate.guardDirection, jt.cell(candidate.location), visited)
+			}
+		}()
+	}
+
+	for i := range candidates {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	obstructions := make([]Location, 0)
+	for i, candidate := range candidates {
+		if loopFormed[i] {
+			obstructions = append(obstructions, candidate.location)
+		}
+	}
+	return obstructions
+}
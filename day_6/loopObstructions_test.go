@@ -0,0 +1,47 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindLoopObstructions(t *testing.T) {
+	mapStr := `....#.....
+.........#
+..........
+..#.......
+.......#..
+..........
+.#..^.....
+........#.
+#.........
+......#...`
+
+	matrix := make([][]rune, 0)
+	for _, line := range strings.Split(mapStr, "\n") {
+		matrix = append(matrix, []rune(line))
+	}
+
+	// in the order the guard reaches them
+	want := []Location{{6, 3}, {7, 6}, {7, 7}, {8, 1}, {8, 3}, {9, 7}}
+	routeOrder := FindLoopObstructions(matrix, 1)
+
+	sorted := slices.Clone(routeOrder)
+	slices.SortFunc(sorted, func(a, b Location) int {
+		if a.row != b.row {
+			return a.row - b.row
+		}
+		return a.col - b.col
+	})
+	if !slices.Equal(sorted, want) {
+		t.Errorf("want %v, got %v", want, sorted)
+	}
+
+	for _, workers := range []int{0, 2, 4, 16} {
+		got := FindLoopObstructions(matrix, workers)
+		if !slices.Equal(got, routeOrder) {
+			t.Errorf("with %d workers want %v, got %v", workers, routeOrder, got)
+		}
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"slices"
+	"runtime"
 )
 
 const Obstacle = '#'
@@ -141,8 +141,10 @@ func Patrol(matrix [][]rune) (patrolRoute []VisitingRecord, loopFormed bool) {
 
 func GetUniqueLocations(allpatrolledLocations []VisitingRecord) []Location {
 	uniqueLocations := make([]Location, 0, len(allpatrolledLocations))
+	seen := make(map[Location]bool, len(allpatrolledLocations))
 	for _, visitingRecord := range allpatrolledLocations {
-		if !slices.Contains(uniqueLocations, visitingRecord.location) {
+		if !seen[visitingRecord.location] {
+			seen[visitingRecord.location] = true
 			uniqueLocations = append(uniqueLocations, visitingRecord.location)
 		}
 	}
@@ -158,7 +160,13 @@ func CopyMatrix(matrix [][]rune) [][]rune {
 	return newMatrix
 }
 
+// count the locations where an extra obstacle makes the guard patrol in a loop, using a jump table of the map
 func GetPatrolLoopOpportunities(matrix [][]rune) int {
+	return len(FindLoopObstructions(matrix, runtime.NumCPU()))
+}
+
+// the brute force version of GetPatrolLoopOpportunities, patrolling a copy of the whole map for every candidate obstacle
+func GetPatrolLoopOpportunitiesBruteForce(matrix [][]rune) int {
 
 	patrolRoute, _ := Patrol(matrix)
 
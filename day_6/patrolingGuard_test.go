@@ -49,7 +49,7 @@ func TestGetPatrolLoopOpportunities(t *testing.T) {
 		matrix = append(matrix, []rune(line))
 	}
 
-	t.Run("GetLoopOpportunitiesBF", func(t *testing.T) {
+	t.Run("GetLoopOpportunities", func(t *testing.T) {
 
 		want := 6
 		get := GetPatrolLoopOpportunities(matrix)
@@ -59,4 +59,14 @@ func TestGetPatrolLoopOpportunities(t *testing.T) {
 		}
 	})
 
+	t.Run("GetLoopOpportunitiesBF", func(t *testing.T) {
+
+		want := 6
+		get := GetPatrolLoopOpportunitiesBruteForce(matrix)
+
+		if want != get {
+			t.Errorf("want %d, get %d", want, get)
+		}
+	})
+
 }
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+)
+
+// an operator the backward solver can peel off the goal: given 'a op b' and b, Unapply finds a.
+// ok is false when no non-negative a exists. Numbers are non-negative, see absorbs for when any a works
+type InvertibleOperator interface {
+	Operator
+	Unapply(result, b int64) (a int64, ok bool)
+	UnapplyBig(result, b *big.Int) (a *big.Int, ok bool)
+}
+
+func (Add) Unapply(result, b int64) (int64, bool) {
+	return result - b, result >= b
+}
+
+func (Add) UnapplyBig(result, b *big.Int) (*big.Int, bool) {
+	if result.Cmp(b) < 0 {
+		return nil, false
+	}
+	return new(big.Int).Sub(result, b), true
+}
+
+func (Multiply) Unapply(result, b int64) (int64, bool) {
+	if b == 0 || result%b != 0 {
+		return 0, false
+	}
+	return result / b, true
+}
+
+func (Multiply) UnapplyBig(result, b *big.Int) (*big.Int, bool) {
+	if b.Sign() == 0 {
+		return nil, false
+	}
+	a, remainder := new(big.Int).QuoRem(result, b, new(big.Int))
+	return a, remainder.Sign() == 0
+}
+
+// strip the digits of b from the end of result
+func (Concatenate) Unapply(result, b int64) (int64, bool) {
+	if result < b {
+		return 0, false
+	}
+	shift := int64(10)
+	for shift <= b {
+		if shift > math.MaxInt64/10 {
+			// b has all the digits an int64 can have, only 0 || b is possible
+			return 0, result == b
+		}
+		shift *= 10
+	}
+	return result / shift, result%shift == b
+}
+
+func (Concatenate) UnapplyBig(result, b *big.Int) (*big.Int, bool) {
+	if result.Cmp(b) < 0 {
+		return nil, false
+	}
+	shift := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(len(b.String()))), nil)
+	a, remainder := new(big.Int).QuoRem(result, shift, new(big.Int))
+	return a, remainder.Cmp(b) == 0
+}
+
+var invertibleOperators = []InvertibleOperator{Add{}, Multiply{}, Concatenate{}}
+
+// whether 'a op b' is the result for any a, so Unapply can't name a: a * 0 = 0
+func absorbs(operator InvertibleOperator, result, b int64) bool {
+	_, isMultiply := operator.(Multiply)
+	return isMultiply && b == 0 && result == 0
+}
+
+func absorbsBig(operator InvertibleOperator, result, b *big.Int) bool {
+	_, isMultiply := operator.(Multiply)
+	return isMultiply && b.Sign() == 0 && result.Sign() == 0
+}
+
+// choose operators between the first last+1 numbers such that the expression can be evaluated, whatever its result,
+// trying them forwards. Only fails when every choice overflows
+func chooseEvaluable(numbers []int64, last int, operators []InvertibleOperator, chosen []Operator) bool {
+	var search func(result int64, next int) bool
+	search = func(result int64, next int) bool {
+		if next > last {
+			return true
+		}
+		for _, operator := range operators {
+			if newResult, ok := operator.Apply(result, numbers[next]); ok {
+				chosen[next-1] = operator
+				if search(newResult, next+1) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return search(numbers[0], 1)
+}
+
+// the operators as InvertibleOperators, ok is false when one of them can't be undone
+func invertibleOnly(operators []Operator) (inverses []InvertibleOperator, ok bool) {
+	inverses = make([]InvertibleOperator, 0, len(operators))
+	for _, operator := range operators {
+		inverse, ok := operator.(InvertibleOperator)
+		if !ok {
+			return nil, false
+		}
+		inverses = append(inverses, inverse)
+	}
+	return inverses, true
+}
+
+func hasNegative(operatorProblem OperatorProblem) bool {
+	return operatorProblem.goal < 0 || slices.ContainsFunc(operatorProblem.numbers, func(n int64) bool { return n < 0 })
+}
+
+// solve the problem backwards: starting from the goal, peel the last number off with each operator's inverse
+// (subtract, divide, strip the suffix), most paths die right away because the division or the suffix doesn't fit.
+// When multiplying by 0 gives the goal so far, any numbers before work, they are chosen forwards. The inverses need
+// non-negative numbers, problems with negative numbers are solved forwards
+func SolveBackward(operatorProblem OperatorProblem, operators []InvertibleOperator) (expression Expression, found bool) {
+	if hasNegative(operatorProblem) {
+		forwardOperators := make([]Operator, len(operators))
+		for i, operator := range operators {
+			forwardOperators[i] = operator
+		}
+		return FindExpression(operatorProblem, forwardOperators)
+	}
+
+	chosen := make([]Operator, len(operatorProblem.numbers)-1)
+
+	var search func(remaining int64, last int) bool
+	search = func(remaining int64, last int) bool {
+		if last == 0 {
+			return remaining == operatorProblem.numbers[0]
+		}
+		for _, operator := range operators {
+			if absorbs(operator, remaining, operatorProblem.numbers[last]) {
+				chosen[last-1] = operator
+				if chooseEvaluable(operatorProblem.numbers, last-1, operators, chosen) {
+					return true
+				}
+				continue
+			}
+			if a, ok := operator.Unapply(remaining, operatorProblem.numbers[last]); ok {
+				chosen[last-1] = operator
+				if search(a, last-1) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	if !search(operatorProblem.goal, len(operatorProblem.numbers)-1) {
+		return Expression{}, false
+	}
+	return Expression{operatorProblem.numbers, chosen}, true
+}
+
+// an operator problem whose numbers don't fit in an int64
+type BigOperatorProblem struct {
+	goal    *big.Int
+	numbers []*big.Int
+}
+
+func (p BigOperatorProblem) String() string {
+	return fmt.Sprintf("%v: %v", p.goal, p.numbers)
+}
+
+func ParseBigOperatorProblem(line string) (BigOperatorProblem, error) {
+	words := strings.Fields(line)
+	if len(words) < 2 || !strings.HasSuffix(words[0], ":") {
+		return BigOperatorProblem{}, fmt.Errorf("invalid operator problem %q", line)
+	}
+
+	goal, ok := new(big.Int).SetString(strings.TrimSuffix(words[0], ":"), 10)
+	if !ok {
+		return BigOperatorProblem{}, fmt.Errorf("invalid goal %q", words[0])
+	}
+
+	numbers := make([]*big.Int, 0, len(words)-1)
+	for _, word := range words[1:] {
+		number, ok := new(big.Int).SetString(word, 10)
+		if !ok {
+			return BigOperatorProblem{}, fmt.Errorf("invalid number %q", word)
+		}
+		numbers = append(numbers, number)
+	}
+
+	return BigOperatorProblem{goal, numbers}, nil
+}
+
+func (p BigOperatorProblem) hasNegative() bool {
+	return p.goal.Sign() < 0 || slices.ContainsFunc(p.numbers, func(n *big.Int) bool { return n.Sign() < 0 })
+}
+
+// the backward solver on math/big numbers, returns the operators between the numbers. The numbers must not be
+// negative. math/big numbers don't overflow, so when multiplying by 0 gives the goal so far, the numbers before are
+// combined with the first operator
+func SolveBackwardBig(operatorProblem BigOperatorProblem, operators []InvertibleOperator) (chosen []Operator, found bool) {
+	chosen = make([]Operator, len(operatorProblem.numbers)-1)
+
+	var search func(remaining *big.Int, last int) bool
+	search = func(remaining *big.Int, last int) bool {
+		if last == 0 {
+			return remaining.Cmp(operatorProblem.numbers[0]) == 0
+		}
+		for _, operator := range operators {
+			if absorbsBig(operator, remaining, operatorProblem.numbers[last]) {
+				chosen[last-1] = operator
+				for i := range last - 1 {
+					chosen[i] = operators[0]
+				}
+				return true
+			}
+			if a, ok := operator.UnapplyBig(remaining, operatorProblem.numbers[last]); ok {
+				chosen[last-1] = operator
+				if search(a, last-1) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	if !search(operatorProblem.goal, len(operatorProblem.numbers)-1) {
+		return nil, false
+	}
+	return chosen, true
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestSolveBackward(t *testing.T) {
+	testCases := []struct {
+		line     string
+		expected bool
+	}{
+		{"190: 10 19", true},
+		{"3267: 81 40 27", true},
+		{"83: 17 5", false},
+		{"156: 15 6", true},
+		{"7290: 6 8 6 15", true},
+		{"161011: 16 10 13", false},
+		{"192: 17 8 14", true},
+		{"21037: 9 7 18 13", false},
+		{"292: 11 6 16 20", true},
+		// multiplying by 0 gives 0 whatever comes before
+		{"5: 10 0 5", true},
+		{"0: 3 0", true},
+		{"0: 10 5 0", true},
+		{"7: 10 0 5", false},
+		{"9: 10 0 5 0 9", true},
+		// negative numbers are solved forwards
+		{"-15: 10 -2 5", true},
+		{"-3: 10 -2", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			problem := mustParseOperatorProblem(tc.line)
+			expression, found := SolveBackward(problem, invertibleOperators)
+			if found != tc.expected {
+				t.Fatalf("Expected %v, got %v", tc.expected, found)
+			}
+			if found {
+				if result, ok := expression.Evaluate(); !ok || result != problem.goal {
+					t.Errorf("expression %v evaluates to %d, want %d", expression, result, problem.goal)
+				}
+			}
+		})
+	}
+}
+
+func TestSolveBackwardBig(t *testing.T) {
+	testCases := []struct {
+		line     string
+		expected bool
+	}{
+		{"7290: 6 8 6 15", true},
+		{"21037: 9 7 18 13", false},
+		// goals beyond int64
+		{"123456789012345678901234567890: 123456789012345 678901234567890", true},
+		{"99999999999999999999999999999999: 99999999999999999 1", false},
+		{"18446744073709551616: 4294967296 4294967296", true},
+		{"5: 123456789012345678901234567890 0 5", true},
+		{"0: 123456789012345678901234567890 7 0", true},
+		{"7: 123456789012345678901234567890 0 5", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			problem, err := ParseBigOperatorProblem(tc.line)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, found := SolveBackwardBig(problem, invertibleOperators)
+			if found != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, found)
+			}
+		})
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, line := range []string{"12 3 4", "12:", "x: 1 2", "12: 1 y"} {
+			if _, err := ParseBigOperatorProblem(line); err == nil {
+				t.Errorf("want an error for %q", line)
+			}
+		}
+	})
+}
+
+// random problems like the puzzle input: half of them solvable
+func randomOperatorProblems(n int) []OperatorProblem {
+	random := rand.New(rand.NewSource(7))
+	operators := []Operator{Add{}, Multiply{}, Concatenate{}}
+
+	problems := make([]OperatorProblem, 0, n)
+	for len(problems) < n {
+		numbers := make([]int64, 3+random.Intn(9))
+		for i := range numbers {
+			numbers[i] = int64(1 + random.Intn(999))
+		}
+
+		chosen := make([]Operator, len(numbers)-1)
+		for i := range chosen {
+			chosen[i] = operators[random.Intn(len(operators))]
+		}
+		goal, ok := Expression{numbers, chosen}.Evaluate()
+		if !ok {
+			continue
+		}
+		if random.Intn(2) == 0 {
+			goal++
+		}
+		problems = append(problems, OperatorProblem{goal, numbers})
+	}
+	return problems
+}
+
+func TestSolveBackwardMatchesForward(t *testing.T) {
+	for _, problem := range randomOperatorProblems(300) {
+		_, forward := FindExpression(problem, []Operator{Add{}, Multiply{}, Concatenate{}})
+		_, backward := SolveBackward(problem, invertibleOperators)
+		if forward != backward {
+			t.Errorf("%v: forward %v, backward %v", problem, forward, backward)
+		}
+	}
+}
+
+func TestSolveBackwardMatchesForwardWithZeros(t *testing.T) {
+	random := rand.New(rand.NewSource(11))
+	for range 1000 {
+		numbers := make([]int64, 2+random.Intn(4))
+		for i := range numbers {
+			numbers[i] = int64(random.Intn(4))
+		}
+		problem := OperatorProblem{int64(random.Intn(40)), numbers}
+
+		_, forward := FindExpression(problem, []Operator{Add{}, Multiply{}, Concatenate{}})
+		expression, backward := SolveBackward(problem, invertibleOperators)
+		if forward != backward {
+			t.Fatalf("%v: forward %v, backward %v", problem, forward, backward)
+		}
+		if backward {
+			if result, ok := expression.Evaluate(); !ok || result != problem.goal {
+				t.Fatalf("expression %v evaluates to %d, want %d", expression, result, problem.goal)
+			}
+		}
+	}
+}
+
+// the original solver, concatenating through strings, kept as the baseline for the benchmarks. It used to panic when
+// a concatenation doesn't fit in an int64, here that path is skipped like any other path above the goal
+func isOperatorProblemSolvableByStrings(operatorProblem OperatorProblem) bool {
+	for _, operator := range []string{"+", "*", "||"} {
+		var newNumber int64
+		switch operator {
+		case "+":
+			newNumber = operatorProblem.numbers[0] + operatorProblem.numbers[1]
+		case "*":
+			newNumber = operatorProblem.numbers[0] * operatorProblem.numbers[1]
+		case "||":
+			newNumberStr := fmt.Sprintf("%d%d", operatorProblem.numbers[0], operatorProblem.numbers[1])
+			a, error := strconv.Atoi(newNumberStr)
+			if error != nil {
+				continue // too large for an int64, so above the goal
+			} else {
+				newNumber = int64(a)
+			}
+		}
+
+		// we only have these two numbers left
+		if len(operatorProblem.numbers) == 2 {
+			if newNumber == operatorProblem.goal {
+				return true // go achevied
+			} else {
+				continue // try next operator
+			}
+		} else { // there were more then two numbers in the problem
+
+			if newNumber > operatorProblem.goal {
+				// no solution on this path
+				continue
+			} else {
+				newProblem := OperatorProblem{operatorProblem.goal, append([]int64{newNumber}, operatorProblem.numbers[2:]...)}
+				if isOperatorProblemSolvableByStrings(newProblem) {
+					return true
+				} else {
+					continue
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func TestSolveBackwardMatchesStrings(t *testing.T) {
+	for _, problem := range randomOperatorProblems(300) {
+		_, backward := SolveBackward(problem, invertibleOperators)
+		if want := isOperatorProblemSolvableByStrings(problem); backward != want {
+			t.Errorf("%v: strings %v, backward %v", problem, want, backward)
+		}
+	}
+}
+
+func BenchmarkIsOperatorProblemSolvableByStrings(b *testing.B) {
+	problems := randomOperatorProblems(200)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, problem := range problems {
+			isOperatorProblemSolvableByStrings(problem)
+		}
+	}
+}
+
+func BenchmarkSolveBackward(b *testing.B) {
+	problems := randomOperatorProblems(200)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, problem := range problems {
+			SolveBackward(problem, invertibleOperators)
+		}
+	}
+}
+
+func BenchmarkSolveBackwardBig(b *testing.B) {
+	problems := make([]BigOperatorProblem, 0)
+	for _, problem := range randomOperatorProblems(200) {
+		bigProblem := BigOperatorProblem{big.NewInt(problem.goal), make([]*big.Int, 0, len(problem.numbers))}
+		for _, number := range problem.numbers {
+			bigProblem.numbers = append(bigProblem.numbers, big.NewInt(number))
+		}
+		problems = append(problems, bigProblem)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, problem := range problems {
+			SolveBackwardBig(problem, invertibleOperators)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// a solver for a single problem, e.g. FindExpression with some operators
+type Solver func(OperatorProblem) (Expression, bool)
+
+// the outcome of solving one problem of a batch
+type SolveResult struct {
+	index      int // position of the problem in the batch
+	problem    OperatorProblem
+	expression Expression
+	solvable   bool
+	elapsed    time.Duration
+}
+
+// solve the problems received from the channel on a pool of workers. The results are sent as soon as each problem is
+// solved, so they don't arrive in the order of the problems, use SolveResult.index to match them up.
+// The returned channel is closed once the problems channel is closed and all its problems are solved
+func SolveBatch(problems <-chan OperatorProblem, workers int, solve Solver) <-chan SolveResult {
+	workers = max(workers, 1)
+
+	type job struct {
+		index   int
+		problem OperatorProblem
+	}
+	jobs := make(chan job)
+	results := make(chan SolveResult, workers)
+
+	go func() {
+		index := 0
+		for problem := range problems {
+			jobs <- job{index, problem}
+			index++
+		}
+		close(jobs)
+	}()
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				start := time.Now()
+				expression, solvable := solve(j.problem)
+				results <- SolveResult{j.index, j.problem, expression, solvable, time.Since(start)}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
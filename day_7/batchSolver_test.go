@@ -0,0 +1,73 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSolveBatch(t *testing.T) {
+	problems := randomOperatorProblems(200)
+	solve := func(p OperatorProblem) (Expression, bool) { return SolveBackward(p, invertibleOperators) }
+
+	for _, workers := range []int{0, 1, 4} {
+		input := make(chan OperatorProblem)
+		go func() {
+			for _, problem := range problems {
+				input <- problem
+			}
+			close(input)
+		}()
+
+		seen := make([]bool, len(problems))
+		for result := range SolveBatch(input, workers, solve) {
+			if seen[result.index] {
+				t.Fatalf("problem %d solved twice", result.index)
+			}
+			seen[result.index] = true
+
+			if !slices.Equal(result.problem.numbers, problems[result.index].numbers) {
+				t.Errorf("result %d is for problem %v, want %v", result.index, result.problem, problems[result.index])
+			}
+			_, want := solve(problems[result.index])
+			if result.solvable != want {
+				t.Errorf("problem %v: want %v, got %v", result.problem, want, result.solvable)
+			}
+			if result.elapsed < 0 {
+				t.Errorf("problem %v: negative timing", result.problem)
+			}
+		}
+
+		if slices.Contains(seen, false) {
+			t.Errorf("with %d workers not all problems were solved", workers)
+		}
+	}
+}
+
+// an operator which never applies, so registering it doesn't change which problems are solvable
+type never struct{}
+
+func (never) Apply(a, b int64) (int64, bool) { return 0, false }
+
+func (never) String() string { return "?" }
+
+func TestRegisterOperatorWhileSolving(t *testing.T) {
+	registered := RegisteredOperators()
+	t.Cleanup(func() { operatorRegistry = registered })
+
+	problems := randomOperatorProblems(200)
+	input := make(chan OperatorProblem)
+	go func() {
+		for _, problem := range problems {
+			input <- problem
+			RegisterOperator(never{})
+		}
+		close(input)
+	}()
+
+	solve := func(p OperatorProblem) (Expression, bool) { return Expression{}, IsOperatorProblemSolvable(p) }
+	for result := range SolveBatch(input, 4, solve) {
+		if _, want := FindExpression(problems[result.index], registered); result.solvable != want {
+			t.Errorf("problem %v: want %v, got %v", result.problem, want, result.solvable)
+		}
+	}
+}
@@ -4,10 +4,14 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"math/big"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // we have a bunch of numbers and a goal. we'd like to find operators that when operated on the numbers, the result is the goal
@@ -20,90 +24,195 @@ func (p OperatorProblem) String() string {
 	return fmt.Sprintf("%d: %v", p.goal, p.numbers)
 }
 
-func IsOperatorProblemSolvable(operatorProblem OperatorProblem) bool {
-	for _, operator := range []string{"+", "*", "||"} {
-		var newNumber int64
-		switch operator {
-		case "+":
-			newNumber = operatorProblem.numbers[0] + operatorProblem.numbers[1]
-		case "*":
-			newNumber = operatorProblem.numbers[0] * operatorProblem.numbers[1]
-		case "||":
-			newNumberStr := fmt.Sprintf("%d%d", operatorProblem.numbers[0], operatorProblem.numbers[1])
-			a, error := strconv.Atoi(newNumberStr)
-			if error != nil {
-				panic(error)
-			} else {
-				newNumber = int64(a)
-			}
+// the numbers of a problem with the operators between them, evaluated left to right
+type Expression struct {
+	numbers   []int64
+	operators []Operator
+}
+
+func (e Expression) String() string {
+	s := fmt.Sprintf("%d", e.numbers[0])
+	for i, operator := range e.operators {
+		s += fmt.Sprintf(" %v %d", operator, e.numbers[i+1])
+	}
+	return s
+}
+
+func (e Expression) Evaluate() (result int64, ok bool) {
+	result = e.numbers[0]
+	for i, operator := range e.operators {
+		if result, ok = operator.Apply(result, e.numbers[i+1]); !ok {
+			return 0, false
 		}
+	}
+	return result, true
+}
 
-		// we only have these two numbers left
-		if len(operatorProblem.numbers) == 2 {
-			if newNumber == operatorProblem.goal {
-				return true // go achevied
-			} else {
-				continue // try next operator
+// try the operators between the numbers, from left to right. found is called with the operators of every
+// expression reaching the goal, the search stops when it returns false
+func searchOperators(operatorProblem OperatorProblem, operators []Operator, found func([]Operator) bool) {
+	pruneAboveGoal := allGrowing(operators)
+	// the numbers from positiveFrom on are all positive, growing operators can't bring the result back down with them
+	positiveFrom := len(operatorProblem.numbers)
+	for positiveFrom > 1 && operatorProblem.numbers[positiveFrom-1] > 0 {
+		positiveFrom--
+	}
+	chosen := make([]Operator, len(operatorProblem.numbers)-1)
+
+	// returns false when the search should stop
+	var search func(result int64, next int) bool
+	search = func(result int64, next int) bool {
+		if next == len(operatorProblem.numbers) {
+			if result == operatorProblem.goal {
+				return found(append([]Operator{}, chosen...))
 			}
-		} else { // there were more then two numbers in the problem
+			return true
+		}
+
+		if pruneAboveGoal && next >= positiveFrom && result >= 0 && result > operatorProblem.goal {
+			// no solution on this path
+			return true
+		}
 
-			if newNumber > operatorProblem.goal {
-				// no solution on this path
+		for _, operator := range operators {
+			newNumber, ok := operator.Apply(result, operatorProblem.numbers[next])
+			if !ok {
 				continue
-			} else {
-				newProblem := OperatorProblem{operatorProblem.goal, append([]int64{newNumber}, operatorProblem.numbers[2:]...)}
-				if IsOperatorProblemSolvable(newProblem) {
-					return true
-				} else {
-					continue
-				}
+			}
+			chosen[next-1] = operator
+			if !search(newNumber, next+1) {
+				return false
 			}
 		}
+		return true
 	}
 
-	return false
+	search(operatorProblem.numbers[0], 1)
+}
+
+// find an expression reaching the goal with the given operators
+func FindExpression(operatorProblem OperatorProblem, operators []Operator) (expression Expression, found bool) {
+	searchOperators(operatorProblem, operators, func(chosen []Operator) bool {
+		expression = Expression{operatorProblem.numbers, chosen}
+		found = true
+		return false
+	})
+	return
+}
+
+// find all the expressions reaching the goal with the given operators
+func FindAllExpressions(operatorProblem OperatorProblem, operators []Operator) []Expression {
+	expressions := make([]Expression, 0)
+	searchOperators(operatorProblem, operators, func(chosen []Operator) bool {
+		expressions = append(expressions, Expression{operatorProblem.numbers, chosen})
+		return true
+	})
+	return expressions
 }
 
-func ParseOperatorProblem(line string) OperatorProblem {
+func IsOperatorProblemSolvable(operatorProblem OperatorProblem) bool {
+	_, found := FindExpression(operatorProblem, RegisteredOperators())
+	return found
+}
+
+// parse a line like "190: 10 19". Numbers which don't fit in an int64 give an error wrapping strconv.ErrRange,
+// such lines can be parsed with ParseBigOperatorProblem
+func ParseOperatorProblem(line string) (OperatorProblem, error) {
 	words := strings.Fields(line)
+	if len(words) < 2 || !strings.HasSuffix(words[0], ":") {
+		return OperatorProblem{}, fmt.Errorf("invalid operator problem %q", line)
+	}
 
 	// throw away the last ':' and convert to int
-	goal, _ := strconv.Atoi(words[0][:len(words[0])-1])
-	numbers := []int64{}
+	goal, err := strconv.ParseInt(strings.TrimSuffix(words[0], ":"), 10, 64)
+	if err != nil {
+		return OperatorProblem{}, fmt.Errorf("invalid goal %q: %w", words[0], err)
+	}
+	numbers := make([]int64, 0, len(words)-1)
 	for _, word := range words[1:] {
-		number, _ := strconv.Atoi(word)
-		numbers = append(numbers, int64(number))
+		number, err := strconv.ParseInt(word, 10, 64)
+		if err != nil {
+			return OperatorProblem{}, fmt.Errorf("invalid number %q: %w", word, err)
+		}
+		numbers = append(numbers, number)
 	}
 
-	return OperatorProblem{int64(goal), numbers}
+	return OperatorProblem{goal, numbers}, nil
 }
 
 func main() {
 
-	operatorProblems := []OperatorProblem{}
-
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		operatorProblems = append(operatorProblems, ParseOperatorProblem(line))
+	// stream the problems to the solvers while they are read. Problems with numbers beyond an int64 are kept
+	// aside for the math/big solver
+	operatorProblems := make(chan OperatorProblem)
+	bigProblems := make([]BigOperatorProblem, 0)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for lineNumber := 1; scanner.Scan(); lineNumber++ {
+			line := scanner.Text()
+
+			operatorProblem, err := ParseOperatorProblem(line)
+			if errors.Is(err, strconv.ErrRange) {
+				var bigProblem BigOperatorProblem
+				if bigProblem, err = ParseBigOperatorProblem(line); err == nil {
+					bigProblems = append(bigProblems, bigProblem)
+					continue
+				}
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "skipping line", lineNumber, err)
+				continue
+			}
+			operatorProblems <- operatorProblem
+		}
+		close(operatorProblems)
+	}()
+
+	// the backward solver when it can undo every operator
+	operators := RegisteredOperators()
+	solve := func(p OperatorProblem) (Expression, bool) { return FindExpression(p, operators) }
+	inverses, invertible := invertibleOnly(operators)
+	if invertible {
+		solve = func(p OperatorProblem) (Expression, bool) { return SolveBackward(p, inverses) }
 	}
 
-	fmt.Println("number of operator problems: ", len(operatorProblems))
-
-	solvableProblems := []OperatorProblem{}
+	start := time.Now()
+	solved := 0
+	sum := new(big.Int)
+	var slowest SolveResult
+	for result := range SolveBatch(operatorProblems, runtime.NumCPU(), solve) {
+		solved++
+		if result.solvable {
+			sum.Add(sum, big.NewInt(result.problem.goal))
+		}
+		if result.elapsed > slowest.elapsed {
+			slowest = result
+		}
 
-	for _, operatorProblem := range operatorProblems {
-		if IsOperatorProblemSolvable(operatorProblem) {
-			solvableProblems = append(solvableProblems, operatorProblem)
+		if solved%100 == 0 {
+			fmt.Println("solved", solved, "problems in", time.Since(start))
 		}
 	}
+	if solved > 0 {
+		fmt.Println("slowest problem:", slowest.problem, "took", slowest.elapsed)
+	}
 
-	var sum int64 = 0
-	for _, p := range solvableProblems {
-		sum += p.goal
+	// bigProblems is complete: the reader closed the channel after its last append
+	for _, bigProblem := range bigProblems {
+		solved++
+		switch {
+		case !invertible:
+			fmt.Fprintln(os.Stderr, "skipping", bigProblem, "the registered operators can't solve numbers beyond an int64")
+		case bigProblem.hasNegative():
+			fmt.Fprintln(os.Stderr, "skipping", bigProblem, "negative numbers beyond an int64 aren't supported")
+		default:
+			if _, found := SolveBackwardBig(bigProblem, inverses); found {
+				sum.Add(sum, bigProblem.goal)
+			}
+		}
 	}
 
+	fmt.Println("number of operator problems: ", solved)
 	fmt.Println("sum of solvable problems goals: ", sum)
 
 }
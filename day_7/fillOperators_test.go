@@ -1,9 +1,45 @@
 package main
 
 import (
+	"errors"
+	"strconv"
 	"testing"
 )
 
+func mustParseOperatorProblem(line string) OperatorProblem {
+	operatorProblem, err := ParseOperatorProblem(line)
+	if err != nil {
+		panic(err)
+	}
+	return operatorProblem
+}
+
+func TestParseOperatorProblem(t *testing.T) {
+	got := mustParseOperatorProblem("190: 10 19")
+	if got.goal != 190 || len(got.numbers) != 2 || got.numbers[0] != 10 || got.numbers[1] != 19 {
+		t.Errorf("got %v, want 190: 10 19", got)
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, line := range []string{"", "12 3 4", "12:", "x: 1 2", "12: 1 y"} {
+			if _, err := ParseOperatorProblem(line); err == nil {
+				t.Errorf("want an error for %q", line)
+			}
+		}
+	})
+
+	t.Run("beyond an int64", func(t *testing.T) {
+		for _, line := range []string{"99999999999999999999: 1 2", "12: 99999999999999999999 2"} {
+			if _, err := ParseOperatorProblem(line); !errors.Is(err, strconv.ErrRange) {
+				t.Errorf("%q: want an error wrapping strconv.ErrRange, got %v", line, err)
+			}
+			if _, err := ParseBigOperatorProblem(line); err != nil {
+				t.Errorf("%q: unexpected error from ParseBigOperatorProblem: %v", line, err)
+			}
+		}
+	})
+}
+
 func TestIsOperatorProblemSolvable(t *testing.T) {
 
 	// test cases
@@ -11,15 +47,20 @@ func TestIsOperatorProblemSolvable(t *testing.T) {
 		operatorProblem OperatorProblem
 		expected        bool
 	}{
-		{ParseOperatorProblem("190: 10 19"), true},
-		{ParseOperatorProblem("3267: 81 40 27"), true},
-		{ParseOperatorProblem("83: 17 5"), false},
-		{ParseOperatorProblem("156: 15 6"), true},
-		{ParseOperatorProblem("7290: 6 8 6 15"), true},
-		{ParseOperatorProblem("161011: 16 10 13"), false},
-		{ParseOperatorProblem("192: 17 8 14"), true},
-		{ParseOperatorProblem("21037: 9 7 18 13"), false},
-		{ParseOperatorProblem("292: 11 6 16 20"), true},
+		{mustParseOperatorProblem("190: 10 19"), true},
+		{mustParseOperatorProblem("3267: 81 40 27"), true},
+		{mustParseOperatorProblem("83: 17 5"), false},
+		{mustParseOperatorProblem("156: 15 6"), true},
+		{mustParseOperatorProblem("7290: 6 8 6 15"), true},
+		{mustParseOperatorProblem("161011: 16 10 13"), false},
+		{mustParseOperatorProblem("192: 17 8 14"), true},
+		{mustParseOperatorProblem("21037: 9 7 18 13"), false},
+		{mustParseOperatorProblem("292: 11 6 16 20"), true},
+		// multiplying by 0 brings the result back below the goal
+		{mustParseOperatorProblem("5: 10 0 5"), true},
+		{mustParseOperatorProblem("0: 3 0"), true},
+		{mustParseOperatorProblem("0: 10 5 0"), true},
+		{mustParseOperatorProblem("7: 10 0 5"), false},
 	}
 
 	for _, tc := range testCases {
@@ -33,3 +74,54 @@ func TestIsOperatorProblemSolvable(t *testing.T) {
 	}
 
 }
+
+func TestFindExpression(t *testing.T) {
+	operators := []Operator{Add{}, Multiply{}, Concatenate{}}
+
+	t.Run("one expression", func(t *testing.T) {
+		expression, found := FindExpression(mustParseOperatorProblem("7290: 6 8 6 15"), operators)
+		if !found {
+			t.Fatalf("want a solution")
+		}
+		if got := expression.String(); got != "6 * 8 || 6 * 15" {
+			t.Errorf("want 6 * 8 || 6 * 15, got %s", got)
+		}
+		if result, _ := expression.Evaluate(); result != 7290 {
+			t.Errorf("want 7290, got %d", result)
+		}
+	})
+
+	t.Run("all expressions", func(t *testing.T) {
+		expressions := FindAllExpressions(mustParseOperatorProblem("3267: 81 40 27"), operators)
+		want := []string{"81 + 40 * 27", "81 * 40 + 27"}
+		if len(expressions) != len(want) {
+			t.Fatalf("want %v, got %v", want, expressions)
+		}
+		for i := range want {
+			if expressions[i].String() != want[i] {
+				t.Errorf("want %s, got %s", want[i], expressions[i])
+			}
+		}
+	})
+
+	t.Run("no expression", func(t *testing.T) {
+		if expressions := FindAllExpressions(mustParseOperatorProblem("83: 17 5"), operators); len(expressions) != 0 {
+			t.Errorf("want no expressions, got %v", expressions)
+		}
+	})
+}
+
+func TestFindAllExpressionsWithZeros(t *testing.T) {
+	operators := []Operator{Add{}, Multiply{}, Concatenate{}}
+
+	expressions := FindAllExpressions(mustParseOperatorProblem("5: 10 0 5"), operators)
+	want := []string{"10 * 0 + 5", "10 * 0 || 5"}
+	if len(expressions) != len(want) {
+		t.Fatalf("want %v, got %v", want, expressions)
+	}
+	for i := range want {
+		if expressions[i].String() != want[i] {
+			t.Errorf("want %s, got %s", want[i], expressions[i])
+		}
+	}
+}
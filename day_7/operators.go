@@ -0,0 +1,108 @@
+package main
+
+import (
+	"math"
+	"sync"
+)
+
+// an operator combines the result so far with the next number of the problem, operators are always evaluated left to right
+type Operator interface {
+	// the result of 'a op b', ok is false when the result can't be computed, e.g. on overflow
+	Apply(a, b int64) (result int64, ok bool)
+	// the symbol of the operator, as printed in an expression
+	String() string
+}
+
+// operators implementing Growing promise that, for a non-negative a and a positive b, the result is never smaller
+// than a. When all the operators of a problem are growing, the solver gives up on a path as soon as it exceeds the goal
+// and all the numbers left are positive. A 0 can still bring the result down, e.g. 10 * 0 = 0
+type Growing interface {
+	Growing() bool
+}
+
+type Add struct{}
+
+func (Add) Apply(a, b int64) (int64, bool) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return 0, false
+	}
+	return a + b, true
+}
+
+func (Add) String() string { return "+" }
+
+func (Add) Growing() bool { return true }
+
+type Multiply struct{}
+
+func (Multiply) Apply(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	result := a * b
+	if result/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	return result, true
+}
+
+func (Multiply) String() string { return "*" }
+
+func (Multiply) Growing() bool { return true }
+
+// concatenate the digits of a and b, e.g. 12 || 345 = 12345
+type Concatenate struct{}
+
+func (Concatenate) Apply(a, b int64) (int64, bool) {
+	if a < 0 || b < 0 {
+		return 0, false
+	}
+
+	shift := int64(10)
+	for shift <= b {
+		if shift > math.MaxInt64/10 {
+			return 0, false
+		}
+		shift *= 10
+	}
+
+	shifted, ok := Multiply{}.Apply(a, shift)
+	if !ok {
+		return 0, false
+	}
+	return Add{}.Apply(shifted, b)
+}
+
+func (Concatenate) String() string { return "||" }
+
+func (Concatenate) Growing() bool { return true }
+
+// the operators the solver uses by default, guarded by operatorRegistryLock as solvers may read it while an operator
+// is being registered
+var (
+	operatorRegistry     = []Operator{Add{}, Multiply{}, Concatenate{}}
+	operatorRegistryLock sync.RWMutex
+)
+
+// add an operator to the ones used by IsOperatorProblemSolvable and main
+func RegisterOperator(operator Operator) {
+	operatorRegistryLock.Lock()
+	defer operatorRegistryLock.Unlock()
+	operatorRegistry = append(operatorRegistry, operator)
+}
+
+// a snapshot of the registered operators, operators registered later don't change it
+func RegisteredOperators() []Operator {
+	operatorRegistryLock.RLock()
+	defer operatorRegistryLock.RUnlock()
+	return append([]Operator{}, operatorRegistry...)
+}
+
+func allGrowing(operators []Operator) bool {
+	for _, operator := range operators {
+		if growing, ok := operator.(Growing); !ok || !growing.Growing() {
+			return false
+		}
+	}
+	return true
+}
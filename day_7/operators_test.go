@@ -0,0 +1,57 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOperators(t *testing.T) {
+	testCases := []struct {
+		operator Operator
+		a, b     int64
+		want     int64
+		wantOk   bool
+	}{
+		{Add{}, 81, 40, 121, true},
+		{Add{}, math.MaxInt64, 1, 0, false},
+		{Multiply{}, 121, 27, 3267, true},
+		{Multiply{}, math.MaxInt64 / 2, 3, 0, false},
+		{Multiply{}, 0, 5, 0, true},
+		{Concatenate{}, 12, 345, 12345, true},
+		{Concatenate{}, 15, 0, 150, true},
+		{Concatenate{}, 15, 10, 1510, true},
+		{Concatenate{}, math.MaxInt64 / 10, 12, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.operator.String(), func(t *testing.T) {
+			got, ok := tc.operator.Apply(tc.a, tc.b)
+			if ok != tc.wantOk || (ok && got != tc.want) {
+				t.Errorf("%d %v %d: want %d %v, got %d %v", tc.a, tc.operator, tc.b, tc.want, tc.wantOk, got, ok)
+			}
+		})
+	}
+}
+
+// subtraction is not growing, so the solver must not prune paths exceeding the goal
+type subtract struct{}
+
+func (subtract) Apply(a, b int64) (int64, bool) { return a - b, true }
+
+func (subtract) String() string { return "-" }
+
+func TestCustomOperator(t *testing.T) {
+	problem := mustParseOperatorProblem("2: 3 4 5")
+
+	if _, found := FindExpression(problem, []Operator{Add{}, Multiply{}}); found {
+		t.Errorf("want no solution without subtraction")
+	}
+
+	expression, found := FindExpression(problem, []Operator{Add{}, Multiply{}, subtract{}})
+	if !found {
+		t.Fatalf("want a solution with subtraction")
+	}
+	if got := expression.String(); got != "3 + 4 - 5" {
+		t.Errorf("want 3 + 4 - 5, got %s", got)
+	}
+}
@@ -68,7 +68,8 @@ func isAlphaNumeric(c rune) bool {
 
 func ParseAntennaMap(data string) [][]rune {
 	antennaMap := [][]rune{}
-	lines := strings.Split(data, "\n")
+	// a trailing newline would add an empty row to the map
+	lines := strings.Split(strings.TrimRight(data, "\n"), "\n")
 	for _, line := range lines {
 		antennaMap = append(antennaMap, []rune(line))
 	}
@@ -93,70 +94,11 @@ func GetRadarLocations(antennaMap [][]rune) map[rune][]Coordinate {
 }
 
 func FindAntiNodesPart1(antennaMap [][]rune) (antiNodes map[Coordinate]bool) {
-	radarLocations := GetRadarLocations(antennaMap)
-
-	antiNodes = make(map[Coordinate]bool)
-
-	for _, locations := range radarLocations {
-		// fmt.Printf("find anti nodes for radar type %c \n", radarType)
-		if len(locations) == 1 {
-			continue
-		}
-
-		for _, locationA := range locations {
-			for _, locationB := range locations {
-
-				if locationA == locationB {
-					continue
-				}
-
-				node1_x := locationA.x - (locationB.x - locationA.x)
-				node1_y := locationA.y - (locationB.y - locationA.y)
-				if node1_x >= 0 && node1_x < len(antennaMap) && node1_y >= 0 && node1_y < len(antennaMap[0]) {
-					antiNodes[Coordinate{node1_x, node1_y}] = true
-				}
-
-				node2_x := locationB.x - (locationA.x - locationB.x)
-				node2_y := locationB.y - (locationA.y - locationB.y)
-				if node2_x >= 0 && node2_x < len(antennaMap) && node2_y >= 0 && node2_y < len(antennaMap[0]) {
-					antiNodes[Coordinate{node2_x, node2_y}] = true
-				}
-			}
-		}
-	}
-
-	return
+	return MergeAntiNodes(FindAntiNodes(antennaMap, Part1Rule))
 }
 
 func FindAntiNodesPart2(antennaMap [][]rune) (antiNodes map[Coordinate]bool) {
-	radarLocations := GetRadarLocations(antennaMap)
-
-	antiNodes = make(map[Coordinate]bool)
-
-	for _, locations := range radarLocations {
-		// fmt.Printf("find anti nodes for radar type %c \n", radarType)
-		if len(locations) == 1 {
-			continue
-		}
-
-		for _, locationA := range locations {
-			for _, locationB := range locations {
-
-				if locationA == locationB {
-					continue
-				}
-
-				colinearPoints := FindAllColinearPoints(locationA, locationB, len(antennaMap), len(antennaMap[0]))
-				for _, point := range colinearPoints {
-					if point.x >= 0 && point.x < len(antennaMap) && point.y >= 0 && point.y < len(antennaMap[0]) {
-						antiNodes[point] = true
-					}
-				}
-			}
-		}
-	}
-
-	return
+	return MergeAntiNodes(FindAntiNodes(antennaMap, Part2Rule))
 }
 
 func main() {
@@ -186,4 +128,8 @@ func main() {
 
 	antiNodes2 := FindAntiNodesPart2(antennaMap)
 	fmt.Println("Antinodes count in Part2:", len(antiNodes2))
+
+	report := AnalyseInterference(antennaMap, Part2Rule)
+	fmt.Println("Antinodes of more than one frequency in Part2:", len(report.sharedCells))
+	fmt.Print(report.RenderOverlay(antennaMap))
 }
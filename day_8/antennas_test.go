@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const example = `............
+........0...
+.....0......
+.......0....
+....0.......
+......A.....
+............
+............
+........A...
+.........A..
+............
+............`
+
+func TestFindAntiNodes(t *testing.T) {
+	antennaMap := ParseAntennaMap(example)
+
+	t.Run("part 1", func(t *testing.T) {
+		if got := len(FindAntiNodesPart1(antennaMap)); got != 14 {
+			t.Errorf("want 14, got %d", got)
+		}
+	})
+
+	t.Run("part 2", func(t *testing.T) {
+		if got := len(FindAntiNodesPart2(antennaMap)); got != 34 {
+			t.Errorf("want 34, got %d", got)
+		}
+	})
+}
+func TestParseAntennaMap(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"no trailing newline", example},
+		{"trailing newline", example + "\n"},
+		{"trailing newlines", example + "\n\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			antennaMap := ParseAntennaMap(tc.data)
+			if len(antennaMap) != 12 {
+				t.Fatalf("want 12 rows, got %d", len(antennaMap))
+			}
+			if got := string(antennaMap[11]); got != strings.Repeat(".", 12) {
+				t.Errorf("want the last row of the map, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+// which points on the line through two antennas of the same frequency are antinodes. The rules are combined:
+// a point is an antinode if any of them applies
+type AntinodeRule struct {
+	// for each ratio r > 1, the points where one antenna is r times as far away as the other, outside the two antennas
+	ratios []int
+	// with ratios, also the points between the two antennas
+	between bool
+	// points at whole multiples of the distance between the two antennas, including the antennas themselves
+	harmonics bool
+	// every grid point on the line through the two antennas
+	allCollinear bool
+	// if > 0, only points no farther than this (in a straight line) from one of the antennas
+	maxDistance int
+}
+
+// the rule of part 1: one antenna twice as far away as the other
+var Part1Rule = AntinodeRule{ratios: []int{2}}
+
+// the rule of part 2: any grid position exactly in line with two antennas
+var Part2Rule = AntinodeRule{allCollinear: true}
+
+func inMap(point Coordinate, maxX, maxY int) bool {
+	return point.x >= 0 && point.x < maxX && point.y >= 0 && point.y < maxY
+}
+
+// all points start + k * step within the bounds, for any integer k
+func pointsOnLine(start, step Coordinate, maxX, maxY int) []Coordinate {
+	points := make([]Coordinate, 0)
+	for p := start; inMap(p, maxX, maxY); p = (Coordinate{p.x - step.x, p.y - step.y}) {
+		points = append(points, p)
+	}
+	for p := (Coordinate{start.x + step.x, start.y + step.y}); inMap(p, maxX, maxY); p = (Coordinate{p.x + step.x, p.y + step.y}) {
+		points = append(points, p)
+	}
+	return points
+}
+
+// the point A + (numerator/denominator) * (B - A), if it is a grid point
+func pointAtFraction(A, B Coordinate, numerator, denominator int) (Coordinate, bool) {
+	dx, dy := (B.x-A.x)*numerator, (B.y-A.y)*numerator
+	if dx%denominator != 0 || dy%denominator != 0 {
+		return Coordinate{}, false
+	}
+	return Coordinate{A.x + dx/denominator, A.y + dy/denominator}, true
+}
+
+func squaredDistance(A, B Coordinate) int {
+	return (A.x-B.x)*(A.x-B.x) + (A.y-B.y)*(A.y-B.y)
+}
+
+// the antinodes produced by the antennas A and B according to the rule, within the bounds
+func (rule AntinodeRule) PairAntiNodes(A, B Coordinate, maxX, maxY int) []Coordinate {
+	candidates := make([]Coordinate, 0)
+
+	if rule.allCollinear {
+		candidates = append(candidates, FindAllColinearPoints(A, B, maxX, maxY)...)
+	}
+
+	if rule.harmonics {
+		candidates = append(candidates, pointsOnLine(A, Coordinate{B.x - A.x, B.y - A.y}, maxX, maxY)...)
+	}
+
+	for _, r := range rule.ratios {
+		// a point A + t * (B - A) is r times as far from A as from B when |t| = r * |t - 1|
+		fractions := [][2]int{}
+		if r > 1 {
+			fractions = append(fractions, [2]int{r, r - 1}, [2]int{-1, r - 1}) // beyond B, beyond A
+		}
+		if rule.between && r > 0 {
+			fractions = append(fractions, [2]int{r, r + 1}, [2]int{1, r + 1})
+		}
+		for _, fraction := range fractions {
+			if point, ok := pointAtFraction(A, B, fraction[0], fraction[1]); ok {
+				candidates = append(candidates, point)
+			}
+		}
+	}
+
+	antiNodes := make([]Coordinate, 0, len(candidates))
+	seen := make(map[Coordinate]bool)
+	for _, point := range candidates {
+		if seen[point] || !inMap(point, maxX, maxY) {
+			continue
+		}
+		maxSquared := rule.maxDistance * rule.maxDistance
+		if rule.maxDistance > 0 && squaredDistance(point, A) > maxSquared && squaredDistance(point, B) > maxSquared {
+			continue
+		}
+		seen[point] = true
+		antiNodes = append(antiNodes, point)
+	}
+	return antiNodes
+}
+
+// find the antinodes of every frequency on the map according to the rule
+func FindAntiNodes(antennaMap [][]rune, rule AntinodeRule) map[rune]map[Coordinate]bool {
+	radarLocations := GetRadarLocations(antennaMap)
+	maxX, maxY := len(antennaMap), len(antennaMap[0])
+
+	antiNodes := make(map[rune]map[Coordinate]bool)
+	for frequency, locations := range radarLocations {
+		antiNodes[frequency] = make(map[Coordinate]bool)
+
+		for i, locationA := range locations {
+			for _, locationB := range locations[i+1:] {
+				for _, point := range rule.PairAntiNodes(locationA, locationB, maxX, maxY) {
+					antiNodes[frequency][point] = true
+				}
+			}
+		}
+	}
+	return antiNodes
+}
+
+// the antinodes of all the frequencies together
+func MergeAntiNodes(antiNodesPerFrequency map[rune]map[Coordinate]bool) map[Coordinate]bool {
+	merged := make(map[Coordinate]bool)
+	for _, antiNodes := range antiNodesPerFrequency {
+		for point := range antiNodes {
+			merged[point] = true
+		}
+	}
+	return merged
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPairAntiNodes(t *testing.T) {
+	testCases := []struct {
+		name string
+		rule AntinodeRule
+		a, b Coordinate
+		want []Coordinate
+	}{
+		{"part 1", Part1Rule, Coordinate{3, 3}, Coordinate{6, 6}, []Coordinate{{0, 0}, {9, 9}}},
+		{"ratio 3", AntinodeRule{ratios: []int{3}}, Coordinate{4, 4}, Coordinate{8, 8}, []Coordinate{{2, 2}}},
+		{"ratio 3 off the grid", AntinodeRule{ratios: []int{3}}, Coordinate{3, 3}, Coordinate{6, 6}, []Coordinate{}},
+		{"ratios 2 and 3", AntinodeRule{ratios: []int{2, 3}}, Coordinate{4, 4}, Coordinate{8, 8}, []Coordinate{{0, 0}, {2, 2}}},
+		{"between", AntinodeRule{ratios: []int{2}, between: true}, Coordinate{3, 3}, Coordinate{6, 6},
+			[]Coordinate{{0, 0}, {4, 4}, {5, 5}, {9, 9}}},
+		{"harmonics", AntinodeRule{harmonics: true}, Coordinate{3, 3}, Coordinate{6, 6},
+			[]Coordinate{{0, 0}, {3, 3}, {6, 6}, {9, 9}}},
+		{"harmonics within 3", AntinodeRule{harmonics: true, maxDistance: 3}, Coordinate{3, 3}, Coordinate{6, 6},
+			[]Coordinate{{3, 3}, {6, 6}}},
+		{"harmonics within 5", AntinodeRule{harmonics: true, maxDistance: 5}, Coordinate{3, 3}, Coordinate{6, 6},
+			[]Coordinate{{0, 0}, {3, 3}, {6, 6}, {9, 9}}},
+		{"part 2", Part2Rule, Coordinate{2, 4}, Coordinate{4, 8}, []Coordinate{{0, 0}, {1, 2}, {2, 4}, {3, 6}, {4, 8}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.rule.PairAntiNodes(tc.a, tc.b, 10, 10)
+			slices.SortFunc(got, compareCoordinates)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+module day_8
+
+go 1.22.1
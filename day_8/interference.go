@@ -0,0 +1,156 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// two antennas of the same frequency and the antinodes they produce
+type AntennaPair struct {
+	a, b      Coordinate
+	antiNodes []Coordinate
+}
+
+type FrequencyReport struct {
+	frequency rune
+	antennas  []Coordinate
+	pairs     []AntennaPair
+	antiNodes []Coordinate // the antinodes of all the pairs, without duplicates
+}
+
+// the antinodes two frequencies have in common
+type FrequencyOverlap struct {
+	frequencies [2]rune
+	cells       []Coordinate
+}
+
+type InterferenceReport struct {
+	rule        AntinodeRule
+	frequencies []FrequencyReport  // ordered by frequency
+	overlaps    []FrequencyOverlap // ordered by the pair of frequencies
+	// cells which are antinodes of more than one frequency, with those frequencies
+	sharedCells map[Coordinate][]rune
+}
+
+func compareCoordinates(a, b Coordinate) int {
+	return cmp.Or(cmp.Compare(a.x, b.x), cmp.Compare(a.y, b.y))
+}
+
+func sortedCoordinates(set map[Coordinate]bool) []Coordinate {
+	coordinates := make([]Coordinate, 0, len(set))
+	for coordinate := range set {
+		coordinates = append(coordinates, coordinate)
+	}
+	slices.SortFunc(coordinates, compareCoordinates)
+	return coordinates
+}
+
+// analyse how the antennas of each frequency produce antinodes according to the rule, and where frequencies interfere
+func AnalyseInterference(antennaMap [][]rune, rule AntinodeRule) InterferenceReport {
+	report := InterferenceReport{rule: rule, sharedCells: make(map[Coordinate][]rune)}
+	maxX, maxY := len(antennaMap), len(antennaMap[0])
+
+	radarLocations := GetRadarLocations(antennaMap)
+	frequencies := make([]rune, 0, len(radarLocations))
+	for frequency := range radarLocations {
+		frequencies = append(frequencies, frequency)
+	}
+	slices.Sort(frequencies)
+
+	antiNodeSets := make(map[rune]map[Coordinate]bool)
+	for _, frequency := range frequencies {
+		locations := radarLocations[frequency]
+		frequencyReport := FrequencyReport{frequency: frequency, antennas: locations}
+
+		antiNodeSets[frequency] = make(map[Coordinate]bool)
+		for i, locationA := range locations {
+			for _, locationB := range locations[i+1:] {
+				pairAntiNodes := rule.PairAntiNodes(locationA, locationB, maxX, maxY)
+				slices.SortFunc(pairAntiNodes, compareCoordinates)
+				frequencyReport.pairs = append(frequencyReport.pairs, AntennaPair{locationA, locationB, pairAntiNodes})
+
+				for _, point := range pairAntiNodes {
+					antiNodeSets[frequency][point] = true
+				}
+			}
+		}
+		frequencyReport.antiNodes = sortedCoordinates(antiNodeSets[frequency])
+
+		for _, point := range frequencyReport.antiNodes {
+			report.sharedCells[point] = append(report.sharedCells[point], frequency)
+		}
+		report.frequencies = append(report.frequencies, frequencyReport)
+	}
+
+	// cells of a single frequency are not shared
+	for point, cellFrequencies := range report.sharedCells {
+		if len(cellFrequencies) < 2 {
+			delete(report.sharedCells, point)
+		}
+	}
+
+	for i, frequencyA := range frequencies {
+		for _, frequencyB := range frequencies[i+1:] {
+			common := make(map[Coordinate]bool)
+			for point := range antiNodeSets[frequencyA] {
+				if antiNodeSets[frequencyB][point] {
+					common[point] = true
+				}
+			}
+			if len(common) > 0 {
+				report.overlaps = append(report.overlaps, FrequencyOverlap{[2]rune{frequencyA, frequencyB}, sortedCoordinates(common)})
+			}
+		}
+	}
+
+	return report
+}
+
+func (r InterferenceReport) String() string {
+	var sb strings.Builder
+
+	for _, frequency := range r.frequencies {
+		fmt.Fprintf(&sb, "frequency %c: %d antennas, %d pairs, %d antinodes\n", frequency.frequency, len(frequency.antennas), len(frequency.pairs), len(frequency.antiNodes))
+		for _, pair := range frequency.pairs {
+			fmt.Fprintf(&sb, "  %v-%v: %v\n", pair.a, pair.b, pair.antiNodes)
+		}
+	}
+
+	for _, overlap := range r.overlaps {
+		fmt.Fprintf(&sb, "frequencies %c and %c share %d antinodes: %v\n", overlap.frequencies[0], overlap.frequencies[1], len(overlap.cells), overlap.cells)
+	}
+
+	fmt.Fprintf(&sb, "cells covered by more than one frequency: %d\n", len(r.sharedCells))
+	return sb.String()
+}
+
+// draw the antinodes over the antenna map: '#' is an antinode of one frequency, '*' of several.
+// Antennas are drawn on top of antinodes
+func (r InterferenceReport) RenderOverlay(antennaMap [][]rune) string {
+	overlay := make([][]rune, len(antennaMap))
+	for i, row := range antennaMap {
+		overlay[i] = make([]rune, len(row))
+		copy(overlay[i], row)
+	}
+
+	for _, frequency := range r.frequencies {
+		for _, point := range frequency.antiNodes {
+			if isAlphaNumeric(overlay[point.x][point.y]) {
+				continue
+			}
+			if len(r.sharedCells[point]) > 1 {
+				overlay[point.x][point.y] = '*'
+			} else {
+				overlay[point.x][point.y] = '#'
+			}
+		}
+	}
+
+	lines := make([]string, len(overlay))
+	for i, row := range overlay {
+		lines[i] = string(row)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAnalyseInterference(t *testing.T) {
+	// the antinodes of a are at 0 and 6, the ones of b at 6 and 9
+	antennaMap := ParseAntennaMap("..a.a..bb.")
+	report := AnalyseInterference(antennaMap, Part1Rule)
+
+	if len(report.frequencies) != 2 {
+		t.Fatalf("want 2 frequencies, got %d", len(report.frequencies))
+	}
+	for i, want := range [][]Coordinate{{{0, 0}, {0, 6}}, {{0, 6}, {0, 9}}} {
+		if got := report.frequencies[i].antiNodes; !slices.Equal(got, want) {
+			t.Errorf("frequency %c: want %v, got %v", report.frequencies[i].frequency, want, got)
+		}
+	}
+
+	if len(report.overlaps) != 1 {
+		t.Fatalf("want 1 overlap, got %v", report.overlaps)
+	}
+	overlap := report.overlaps[0]
+	if overlap.frequencies != [2]rune{'a', 'b'} || !slices.Equal(overlap.cells, []Coordinate{{0, 6}}) {
+		t.Errorf("want a and b sharing 6, got %v", overlap)
+	}
+
+	if len(report.sharedCells) != 1 || !slices.Equal(report.sharedCells[Coordinate{0, 6}], []rune{'a', 'b'}) {
+		t.Errorf("want only 6 shared by a and b, got %v", report.sharedCells)
+	}
+
+	if got, want := report.RenderOverlay(antennaMap), "#.a.a.*bb#\n"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestRenderOverlayExample(t *testing.T) {
+	antennaMap := ParseAntennaMap(example)
+	report := AnalyseInterference(antennaMap, Part1Rule)
+
+	// the antinode under the top A is hidden by the antenna, the one at 1,3 is shared by 0 and A
+	want := `......#....#
+...*....0...
+....#0....#.
+..#....0....
+....0....#..
+.#....A.....
+...#........
+#......#....
+........A...
+.........A..
+..........#.
+..........#.
+`
+	if got := report.RenderOverlay(antennaMap); got != want {
+		t.Errorf("want\n%s\ngot\n%s", want, got)
+	}
+	wantOverlaps := []FrequencyOverlap{{[2]rune{'0', 'A'}, []Coordinate{{1, 3}}}}
+	if !slices.EqualFunc(report.overlaps, wantOverlaps, func(a, b FrequencyOverlap) bool {
+		return a.frequencies == b.frequencies && slices.Equal(a.cells, b.cells)
+	}) {
+		t.Errorf("want %v, got %v", wantOverlaps, report.overlaps)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+// a min-heap of block positions
+type positionHeap []int
+
+func (h positionHeap) Len() int           { return len(h) }
+func (h positionHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h positionHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *positionHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *positionHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// move whole files to the leftmost free span that fits them, in O(n log n).
+// Free spans are indexed by size, with one min-heap of start positions per size, so the leftmost span that fits a file
+// is the smallest top of the heaps for the sizes the file fits in
+func CompactWholeFiles(diskBlocks []DiskSegment) []DiskSegment {
+	disk := NewDisk(diskBlocks)
+	WholeFileMoves{FirstFit}.Compact(disk)
+	return disk.Segments()
+}
+
+// the checksum of a disk layout: the sum of block position times file ID, over all file blocks
+func SegmentsChecksum(diskBlocks []DiskSegment) uint64 {
+	var checksum uint64 = 0
+	position := 0
+	for _, segment := range diskBlocks {
+		if segment.fileID != -1 {
+			for i := 0; i < segment.size; i++ {
+				checksum += uint64((position + i) * segment.fileID)
+			}
+		}
+		position += segment.size
+	}
+	return checksum
+}
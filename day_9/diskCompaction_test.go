@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// a random dense disk map with the given number of digits
+func randomDiskMap(digits int, seed int64) string {
+	random := rand.New(rand.NewSource(seed))
+
+	var sb strings.Builder
+	for i := 0; i < digits; i++ {
+		if i%2 == 0 {
+			sb.WriteByte(byte('1' + random.Intn(9)))
+		} else {
+			sb.WriteByte(byte('0' + random.Intn(10)))
+		}
+	}
+	return sb.String()
+}
+
+// the original whole file defragmenter, moving segments around a slice, kept as the reference for CompactWholeFiles
+func defragmentWithWholeFileMove(diskBlocks []DiskSegment) []DiskSegment {
+	newDiskSegments := make([]DiskSegment, len(diskBlocks))
+	_ = copy(newDiskSegments, diskBlocks)
+
+	rightOffset := 1 // check each fileSegment from the right side
+	for rightOffset < len(newDiskSegments) {
+		// make sure right offet points to a file segment
+		if newDiskSegments[len(newDiskSegments)-rightOffset].fileID == -1 {
+			rightOffset += 1
+			continue
+		}
+		currentFileSegment := newDiskSegments[len(newDiskSegments)-rightOffset]
+
+		// find the first freeSpace segment from the left, that can accomodate this file segment
+		for leftOffset := 0; leftOffset < len(newDiskSegments)-rightOffset; leftOffset++ {
+			if newDiskSegments[leftOffset].fileID == -1 && newDiskSegments[leftOffset].size >= currentFileSegment.size {
+
+				freeSpaceLeftOver := newDiskSegments[leftOffset].size - currentFileSegment.size
+
+				// move the file segment to the left side
+				newDiskSegments[leftOffset].fileID = currentFileSegment.fileID
+				newDiskSegments[leftOffset].size = currentFileSegment.size
+
+				// mark the right side as freeSpace segment
+				newDiskSegments[len(newDiskSegments)-rightOffset].fileID = -1
+
+				// if there are still space left, add it as new Segment to the 'newDiskSegments'
+				if freeSpaceLeftOver > 0 {
+					newDiskSegments = slices.Insert(newDiskSegments, leftOffset+1, DiskSegment{-1, freeSpaceLeftOver})
+				}
+				break
+			}
+		}
+		rightOffset += 1
+	}
+
+	return newDiskSegments
+}
+
+func TestCompactWholeFiles(t *testing.T) {
+	t.Run("example", func(t *testing.T) {
+		diskBlocks := ParseRawDisk("2333133121414131402")
+
+		want := uint64(2858)
+		got := SegmentsChecksum(CompactWholeFiles(diskBlocks))
+		if want != got {
+			t.Errorf("want %d, got %d", want, got)
+		}
+	})
+
+	t.Run("same as the original defragmenter", func(t *testing.T) {
+		for seed := int64(0); seed < 20; seed++ {
+			diskBlocks := ParseRawDisk(randomDiskMap(2001, seed))
+
+			want := SegmentsChecksum(defragmentWithWholeFileMove(diskBlocks))
+			got := SegmentsChecksum(CompactWholeFiles(diskBlocks))
+			if want != got {
+				t.Fatalf("seed %d: want %d, got %d", seed, want, got)
+			}
+		}
+	})
+}
+
+func BenchmarkOriginalDefragmenter(b *testing.B) {
+	diskBlocks := ParseRawDisk(randomDiskMap(100_000, 9))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		defragmentWithWholeFileMove(diskBlocks)
+	}
+}
+
+func BenchmarkCompactWholeFiles(b *testing.B) {
+	diskBlocks := ParseRawDisk(randomDiskMap(100_000, 9))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CompactWholeFiles(diskBlocks)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// merge adjacent free space segments and drop empty free space
+func normalizeSegments(diskBlocks []DiskSegment) []DiskSegment {
+	segments := make([]DiskSegment, 0, len(diskBlocks))
+	for _, segment := range diskBlocks {
+		if segment.size == 0 && segment.fileID == -1 {
+			continue
+		}
+		if segment.fileID == -1 && len(segments) > 0 && segments[len(segments)-1].fileID == -1 {
+			segments[len(segments)-1].size += segment.size
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+// write a disk layout back to the dense disk map format, the reverse of ParseRawDisk.
+// Only layouts with the files in order of their IDs 0, 1, 2..., each in one piece, and no segment larger than 9 blocks can be written
+func FormatRawDisk(diskBlocks []DiskSegment) (string, error) {
+	var sb strings.Builder
+
+	nextFileID := 0
+	expectFile := true
+	for _, segment := range normalizeSegments(diskBlocks) {
+		if segment.size > 9 {
+			return "", fmt.Errorf("segment %v is larger than 9 blocks", segment)
+		}
+
+		if segment.fileID == -1 {
+			if expectFile {
+				return "", fmt.Errorf("free space before file %d", nextFileID)
+			}
+			sb.WriteByte(byte('0' + segment.size))
+			expectFile = true
+			continue
+		}
+
+		if segment.fileID != nextFileID {
+			return "", fmt.Errorf("file %d is out of order, expected file %d", segment.fileID, nextFileID)
+		}
+		if !expectFile {
+			// two files next to each other, with no free space in between
+			sb.WriteByte('0')
+		}
+		sb.WriteByte(byte('0' + segment.size))
+		nextFileID++
+		expectFile = false
+	}
+
+	return sb.String(), nil
+}
+
+// one character per block: the file ID for files 0 to 9, '.' for free space. Larger file IDs are written in brackets, e.g. [12]
+func RenderBlocks(diskBlocks []DiskSegment) string {
+	var sb strings.Builder
+	for _, segment := range diskBlocks {
+		block := "."
+		if segment.fileID >= 10 {
+			block = fmt.Sprintf("[%d]", segment.fileID)
+		} else if segment.fileID >= 0 {
+			block = fmt.Sprintf("%d", segment.fileID)
+		}
+		sb.WriteString(strings.Repeat(block, segment.size))
+	}
+	return sb.String()
+}
+
+// a range of blocks on the disk
+type BlockRange struct {
+	start, size int
+}
+
+func (r BlockRange) String() string {
+	if r.size == 1 {
+		return fmt.Sprintf("%d", r.start)
+	}
+	return fmt.Sprintf("%d-%d", r.start, r.start+r.size-1)
+}
+
+// where the blocks of a file were, and where they are now
+type FileMove struct {
+	fileID   int
+	from, to []BlockRange
+}
+
+func (m FileMove) String() string {
+	return fmt.Sprintf("file %d: %v -> %v", m.fileID, m.from, m.to)
+}
+
+// the block ranges of every file in the layout
+func fileRanges(diskBlocks []DiskSegment) map[int][]BlockRange {
+	ranges := make(map[int][]BlockRange)
+	position := 0
+	for _, segment := range normalizeSegments(diskBlocks) {
+		if segment.fileID != -1 && segment.size > 0 {
+			fileRange := ranges[segment.fileID]
+			// a file split over adjacent segments is still one range
+			if n := len(fileRange); n > 0 && fileRange[n-1].start+fileRange[n-1].size == position {
+				fileRange[n-1].size += segment.size
+			} else {
+				fileRange = append(fileRange, BlockRange{position, segment.size})
+			}
+			ranges[segment.fileID] = fileRange
+		}
+		position += segment.size
+	}
+	return ranges
+}
+
+// the files which moved between the two layouts, by file ID
+func DiffLayouts(before, after []DiskSegment) []FileMove {
+	rangesBefore, rangesAfter := fileRanges(before), fileRanges(after)
+
+	maxFileID := -1
+	for fileID := range rangesBefore {
+		maxFileID = max(maxFileID, fileID)
+	}
+	for fileID := range rangesAfter {
+		maxFileID = max(maxFileID, fileID)
+	}
+
+	moves := make([]FileMove, 0)
+	for fileID := 0; fileID <= maxFileID; fileID++ {
+		from, to := rangesBefore[fileID], rangesAfter[fileID]
+		if !slices.Equal(from, to) {
+			moves = append(moves, FileMove{fileID, from, to})
+		}
+	}
+	return moves
+}
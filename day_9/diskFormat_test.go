@@ -0,0 +1,95 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFormatRawDisk(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		for _, rawDisk := range []string{"2333133121414131402", "12345", "90909", randomDiskMap(1001, 1)} {
+			got, err := FormatRawDisk(ParseRawDisk(rawDisk))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != rawDisk {
+				t.Errorf("want %s, got %s", rawDisk, got)
+			}
+		}
+	})
+
+	t.Run("adjacent free space is merged", func(t *testing.T) {
+		got, err := FormatRawDisk([]DiskSegment{{0, 1}, {-1, 2}, {-1, 3}, {1, 1}})
+		if err != nil || got != "151" {
+			t.Errorf("want 151, got %s, %v", got, err)
+		}
+	})
+
+	t.Run("layouts the format can't hold", func(t *testing.T) {
+		for _, layout := range [][]DiskSegment{
+			{{1, 2}, {0, 2}},
+			{{-1, 2}, {0, 2}},
+			{{0, 2}, {-1, 10}, {1, 1}},
+			{{0, 2}, {1, 1}, {0, 1}},
+		} {
+			if got, err := FormatRawDisk(layout); err == nil {
+				t.Errorf("want an error for %v, got %s", layout, got)
+			}
+		}
+	})
+}
+
+func TestRenderBlocks(t *testing.T) {
+	testCases := []struct {
+		rawDisk string
+		want    string
+	}{
+		{"12345", "0..111....22222"},
+		{"2333133121414131402", "00...111...2...333.44.5555.6666.777.888899"},
+		{"10101010101010101010101", "0123456789[10][11]"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.rawDisk, func(t *testing.T) {
+			got := RenderBlocks(ParseRawDisk(tc.rawDisk))
+			if got != tc.want {
+				t.Errorf("want %s, got %s", tc.want, got)
+			}
+		})
+	}
+
+	t.Run("compacted", func(t *testing.T) {
+		want := "00992111777.44.333....5555.6666.....8888.."
+		got := RenderBlocks(CompactWholeFiles(ParseRawDisk("2333133121414131402")))
+		if got != want {
+			t.Errorf("want %s, got %s", want, got)
+		}
+	})
+}
+
+func TestDiffLayouts(t *testing.T) {
+	before := ParseRawDisk("2333133121414131402")
+	after := CompactWholeFiles(before)
+
+	moves := DiffLayouts(before, after)
+	movedFiles := make([]int, 0)
+	for _, move := range moves {
+		movedFiles = append(movedFiles, move.fileID)
+	}
+	if want := []int{2, 4, 7, 9}; !slices.Equal(movedFiles, want) {
+		t.Errorf("want moved files %v, got %v", want, movedFiles)
+	}
+
+	if got, want := moves[3].String(), "file 9: [40-41] -> [2-3]"; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+
+	t.Run("block moves split files", func(t *testing.T) {
+		disk := NewDisk(before)
+		BlockMoves{}.Compact(disk)
+		moves := DiffLayouts(before, disk.Segments())
+		if got, want := moves[len(moves)-2].String(), "file 8: [36-39] -> [4 8-10]"; got != want {
+			t.Errorf("want %s, got %s", want, got)
+		}
+	})
+}
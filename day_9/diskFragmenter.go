@@ -7,31 +7,6 @@ import (
 	"os"
 )
 
-func ParseRawDisk(rawDisk string) []int {
-	// parse raw disk data format to slices of file_id and free space (represented by '-1')
-	diskBlocks := make([]int, 0)
-	fileID := 0
-	for i, char := range rawDisk {
-		if i%2 == 0 { // this is file size (in terms of data blocks)
-			fileSize := int(char - '0')
-			for j := 0; j < fileSize; j++ {
-				diskBlocks = append(diskBlocks, fileID)
-			}
-			fileID += 1
-		} else { // this is free space
-			freeSpaceSize := int(char - '0')
-			for j := 0; j < freeSpaceSize; j++ {
-				diskBlocks = append(diskBlocks, -1)
-			}
-		}
-		if i == len(rawDisk)-1 {
-			break
-		}
-	}
-
-	return diskBlocks
-}
-
 func FindNextFreeSpace(diskBlocks []int, start int) int {
 	// find the next free space in the disk blocks
 	for i := start; i < len(diskBlocks); i++ {
@@ -61,13 +36,16 @@ func DeFragmentDisk(diskBlocks []int) []int {
 	}
 
 	// now move left all the way to the end (when it meet a free space)
-	for diskBlocks[left] != -1 && left < len(diskBlocks) {
+	for left < len(diskBlocks) && diskBlocks[left] != -1 {
 		left++
 	}
 
 	return diskBlocks[:left]
 }
 
+// the largest disk main draws block by block
+const maxDrawnBlocks = 200
+
 func main() {
 	rawDisk := ""
 	scanner := bufio.NewScanner(os.Stdin)
@@ -78,20 +56,23 @@ func main() {
 
 	fmt.Println("original input: ", rawDisk)
 
-	// parse raw disk data format to slices of file_id and free space (represented by '-1')
-	diskBlocksBeforeDefragmentation := ParseRawDisk(rawDisk)
-
-	fmt.Printf("disk blocks before defragmentation: %v \n", diskBlocksBeforeDefragmentation)
-
-	defragmendDiskBlocks := DeFragmentDisk(diskBlocksBeforeDefragmentation)
+	// parse the raw disk format into a linked list where each node is either file (id= fileID, size=fileSize) or free space (id = -1, size=freespaceSize)
+	diskBlocks := ParseRawDisk(rawDisk)
 
-	fmt.Printf("disk blocks after defragmentation: %v \n", defragmendDiskBlocks)
+	// small disks are drawn block by block, with the files moved by each policy
+	showBlocks := len(NewDisk(diskBlocks).blocks) <= maxDrawnBlocks
+	if showBlocks {
+		fmt.Println("disk blocks before defragmentation:", RenderBlocks(diskBlocks))
+	}
 
-	// calculate checksum
-	checksum := 0
-	for i, fileID := range defragmendDiskBlocks {
-		checksum += i * fileID
+	// compact the disk with every policy and compare the results
+	for _, report := range ComparePolicies(NewDisk(diskBlocks), AllPolicies) {
+		fmt.Println(report)
+		if showBlocks {
+			fmt.Println("  ", RenderBlocks(report.disk.Segments()))
+			for _, move := range DiffLayouts(diskBlocks, report.disk.Segments()) {
+				fmt.Println("  ", move)
+			}
+		}
 	}
-	// print checksum
-	fmt.Println("checksum: ", checksum)
 }
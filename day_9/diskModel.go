@@ -0,0 +1,315 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+)
+
+type DiskSegment struct {
+	fileID int // -1 means free space
+	size   int
+}
+
+func ParseRawDisk(rawDisk string) (diskBlocks []DiskSegment) {
+	diskBlocks = make([]DiskSegment, 0)
+
+	// parse the raw disk format into a linked list where each node is either file (id= fileID, size=fileSize) or free space (id = -1, size=freespaceSize)
+
+	fileID := 0
+	for i, char := range rawDisk {
+		if i%2 == 0 { // this is file size (in terms of data blocks)
+			fileSize := int(char - '0')
+			diskBlocks = append(diskBlocks, DiskSegment{fileID, fileSize})
+			fileID += 1
+		} else { // this is free space
+			freeSpaceSize := int(char - '0')
+			diskBlocks = append(diskBlocks, DiskSegment{-1, freeSpaceSize})
+		}
+	}
+
+	return diskBlocks
+}
+
+// the disk as a list of blocks, each block holds the ID of its file or -1 for free space
+type Disk struct {
+	blocks []int
+}
+
+func NewDisk(diskSegments []DiskSegment) *Disk {
+	disk := &Disk{make([]int, 0)}
+	for _, segment := range diskSegments {
+		for i := 0; i < segment.size; i++ {
+			disk.blocks = append(disk.blocks, segment.fileID)
+		}
+	}
+	return disk
+}
+
+func (d *Disk) Clone() *Disk {
+	return &Disk{slices.Clone(d.blocks)}
+}
+
+// the disk as segments, adjacent blocks of the same file or of free space form one segment
+func (d *Disk) Segments() []DiskSegment {
+	segments := make([]DiskSegment, 0)
+	for _, fileID := range d.blocks {
+		if len(segments) > 0 && segments[len(segments)-1].fileID == fileID {
+			segments[len(segments)-1].size++
+		} else {
+			segments = append(segments, DiskSegment{fileID, 1})
+		}
+	}
+	return segments
+}
+
+func (d *Disk) Checksum() uint64 {
+	var checksum uint64 = 0
+	for i, fileID := range d.blocks {
+		if fileID != -1 {
+			checksum += uint64(i * fileID)
+		}
+	}
+	return checksum
+}
+
+type Fragmentation struct {
+	fragmentedFiles int // files not stored in one piece
+	freeGaps        int // free space segments between file blocks
+}
+
+func (d *Disk) Fragmentation() Fragmentation {
+	fragmentation := Fragmentation{}
+
+	pieces := make(map[int]int)
+	segments := d.Segments()
+	for i, segment := range segments {
+		if segment.fileID != -1 {
+			pieces[segment.fileID]++
+		} else if i > 0 && i < len(segments)-1 {
+			fragmentation.freeGaps++
+		}
+	}
+	for _, count := range pieces {
+		if count > 1 {
+			fragmentation.fragmentedFiles++
+		}
+	}
+	return fragmentation
+}
+
+// a file stored in one piece
+type fileExtent struct {
+	fileID, start, size int
+}
+
+// the files stored in one piece, by decreasing file ID
+func (d *Disk) fileExtents() []fileExtent {
+	extents := make([]fileExtent, 0)
+	pieces := make(map[int]int)
+	position := 0
+	for _, segment := range d.Segments() {
+		if segment.fileID != -1 {
+			pieces[segment.fileID]++
+			extents = append(extents, fileExtent{segment.fileID, position, segment.size})
+		}
+		position += segment.size
+	}
+
+	extents = slices.DeleteFunc(extents, func(e fileExtent) bool { return pieces[e.fileID] > 1 })
+	slices.SortFunc(extents, func(a, b fileExtent) int { return b.fileID - a.fileID })
+	return extents
+}
+
+func (d *Disk) move(from, to, size int) {
+	for i := 0; i < size; i++ {
+		d.blocks[to+i] = d.blocks[from+i]
+		d.blocks[from+i] = -1
+	}
+}
+
+// the free space segments of the disk, indexed by size: one min-heap of start positions per size
+type freeSpanIndex []positionHeap
+
+func newFreeSpanIndex(d *Disk) freeSpanIndex {
+	index := make(freeSpanIndex, 1)
+	position := 0
+	for _, segment := range d.Segments() {
+		if segment.fileID == -1 {
+			for len(index) <= segment.size {
+				index = append(index, positionHeap{})
+			}
+			index[segment.size] = append(index[segment.size], position)
+		}
+		position += segment.size
+	}
+	for size := range index {
+		heap.Init(&index[size])
+	}
+	return index
+}
+
+// the start of the leftmost span of the size, or -1 if there is none
+func (index freeSpanIndex) leftmost(size int) int {
+	if index[size].Len() == 0 {
+		return -1
+	}
+	return index[size][0]
+}
+
+// take the leftmost span of the size out of the index, and put back what is left after using 'used' blocks of it
+func (index freeSpanIndex) use(size, used int) (start int) {
+	start = heap.Pop(&index[size]).(int)
+	if size > used {
+		heap.Push(&index[size-used], start+used)
+	}
+	return start
+}
+
+// the size of the leftmost span before position, of any size, or -1
+func (index freeSpanIndex) leftmostBefore(position int) int {
+	best := -1
+	for size := 1; size < len(index); size++ {
+		if start := index.leftmost(size); start != -1 && start < position && (best == -1 || start < index.leftmost(best)) {
+			best = size
+		}
+	}
+	return best
+}
+
+// Policy compacts a disk, moving file blocks to free space on the left
+type Policy interface {
+	// compact the disk in place, return the number of moves made
+	Compact(disk *Disk) (moves int)
+	String() string
+}
+
+// move single blocks: the last file block to the first free block, until there is no gap left. Every block is a move
+type BlockMoves struct{}
+
+func (BlockMoves) Compact(disk *Disk) int {
+	before := slices.Clone(disk.blocks)
+	DeFragmentDisk(disk.blocks)
+
+	moves := 0
+	for i, fileID := range disk.blocks {
+		if before[i] == -1 && fileID != -1 {
+			moves++
+		}
+	}
+	return moves
+}
+
+func (BlockMoves) String() string { return "block" }
+
+// which free span a whole file is moved to, among the spans on its left big enough for it
+type Fit int
+
+const (
+	FirstFit Fit = iota // the leftmost span
+	BestFit             // the smallest span, leftmost on a tie
+	WorstFit            // the largest span, leftmost on a tie
+)
+
+func (f Fit) String() string {
+	return [...]string{"first-fit", "best-fit", "worst-fit"}[f]
+}
+
+// move whole files, in order of decreasing file ID, each file is tried once. A file which doesn't fit anywhere stays.
+// Space freed by a file is not reused: in a disk read from a disk map it is right of all the files still to move
+type WholeFileMoves struct {
+	fit Fit
+}
+
+func (p WholeFileMoves) Compact(disk *Disk) int {
+	index := newFreeSpanIndex(disk)
+
+	moves := 0
+	for _, file := range disk.fileExtents() {
+		if size := p.chooseSpan(index, file); size != -1 {
+			disk.move(file.start, index.use(size, file.size), file.size)
+			moves++
+		}
+	}
+	return moves
+}
+
+// the size of the span the file is moved to, or -1
+func (p WholeFileMoves) chooseSpan(index freeSpanIndex, file fileExtent) int {
+	chosen := -1
+	for size := file.size; size < len(index); size++ {
+		start := index.leftmost(size)
+		if start == -1 || start >= file.start {
+			continue
+		}
+		switch {
+		case chosen == -1,
+			p.fit == FirstFit && start < index.leftmost(chosen),
+			p.fit == WorstFit:
+			chosen = size
+		}
+	}
+	return chosen
+}
+
+func (p WholeFileMoves) String() string { return "whole-file " + p.fit.String() }
+
+// move whole files to the leftmost span that fits like WholeFileMoves with FirstFit. When a file fits nowhere,
+// split it: fill the free spans on its left, leftmost first, with blocks from the end of the file. Every piece moved is a move
+type PartialMoves struct{}
+
+func (PartialMoves) Compact(disk *Disk) int {
+	index := newFreeSpanIndex(disk)
+	firstFit := WholeFileMoves{FirstFit}
+
+	moves := 0
+	for _, file := range disk.fileExtents() {
+		if size := firstFit.chooseSpan(index, file); size != -1 {
+			disk.move(file.start, index.use(size, file.size), file.size)
+			moves++
+			continue
+		}
+
+		remaining := file.size
+		for remaining > 0 {
+			size := index.leftmostBefore(file.start)
+			if size == -1 {
+				break
+			}
+			piece := min(size, remaining)
+			disk.move(file.start+remaining-piece, index.use(size, piece), piece)
+			remaining -= piece
+			moves++
+		}
+	}
+	return moves
+}
+
+func (PartialMoves) String() string { return "partial" }
+
+var AllPolicies = []Policy{BlockMoves{}, WholeFileMoves{FirstFit}, WholeFileMoves{BestFit}, WholeFileMoves{WorstFit}, PartialMoves{}}
+
+// the result of compacting a disk with one policy
+type PolicyReport struct {
+	policy        Policy
+	moves         int
+	checksum      uint64
+	fragmentation Fragmentation
+	disk          *Disk
+}
+
+func (r PolicyReport) String() string {
+	return fmt.Sprintf("%-20v moves: %7d, fragmented files: %5d, free gaps: %5d, checksum: %d",
+		r.policy, r.moves, r.fragmentation.fragmentedFiles, r.fragmentation.freeGaps, r.checksum)
+}
+
+// compact a copy of the disk with each of the policies
+func ComparePolicies(disk *Disk, policies []Policy) []PolicyReport {
+	reports := make([]PolicyReport, 0, len(policies))
+	for _, policy := range policies {
+		compacted := disk.Clone()
+		moves := policy.Compact(compacted)
+		reports = append(reports, PolicyReport{policy, moves, compacted.Checksum(), compacted.Fragmentation(), compacted})
+	}
+	return reports
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestComparePolicies(t *testing.T) {
+	disk := NewDisk(ParseRawDisk("2333133121414131402"))
+	reports := ComparePolicies(disk, AllPolicies)
+
+	want := map[string]uint64{
+		"block":                1928,
+		"whole-file first-fit": 2858,
+		"partial":              1928,
+	}
+	for _, report := range reports {
+		if checksum, ok := want[report.policy.String()]; ok && checksum != report.checksum {
+			t.Errorf("%v: want checksum %d, got %d", report.policy, checksum, report.checksum)
+		}
+	}
+
+	t.Run("the disk itself is not compacted", func(t *testing.T) {
+		if !slices.Equal(disk.blocks, NewDisk(ParseRawDisk("2333133121414131402")).blocks) {
+			t.Errorf("disk changed to %v", disk.blocks)
+		}
+	})
+
+	t.Run("block moves count blocks", func(t *testing.T) {
+		if reports[0].moves != 12 {
+			t.Errorf("want 12 moves, got %d", reports[0].moves)
+		}
+		if reports[0].fragmentation.freeGaps != 0 {
+			t.Errorf("want no free gaps, got %d", reports[0].fragmentation.freeGaps)
+		}
+	})
+}
+
+func TestWholeFileMoves(t *testing.T) {
+	// file 2 fits in the free spans at 1 (size 5) and at 7 (size 2)
+	testCases := []struct {
+		fit  Fit
+		want []int
+	}{
+		{FirstFit, []int{0, 2, 2, 1, -1, -1, -1, -1, -1, -1, -1}},
+		{BestFit, []int{0, 1, -1, -1, -1, -1, -1, 2, 2, -1, -1}},
+		{WorstFit, []int{0, 2, 2, 1, -1, -1, -1, -1, -1, -1, -1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.fit.String(), func(t *testing.T) {
+			disk := NewDisk(ParseRawDisk("15122"))
+			WholeFileMoves{tc.fit}.Compact(disk)
+			if !slices.Equal(disk.blocks, tc.want) {
+				t.Errorf("want %v, got %v", tc.want, disk.blocks)
+			}
+		})
+	}
+}
+
+func TestPartialMoves(t *testing.T) {
+	disk := NewDisk(ParseRawDisk("12123"))
+	moves := PartialMoves{}.Compact(disk)
+
+	want := []int{0, 2, 2, 1, 2, -1, -1, -1, -1}
+	if !slices.Equal(disk.blocks, want) {
+		t.Errorf("want %v, got %v", want, disk.blocks)
+	}
+	if moves != 2 {
+		t.Errorf("want 2 moves, got %d", moves)
+	}
+	if got := disk.Fragmentation().fragmentedFiles; got != 1 {
+		t.Errorf("want 1 fragmented file, got %d", got)
+	}
+}
@@ -0,0 +1,3 @@
+module day_9
+
+go 1.22.1